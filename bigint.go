@@ -0,0 +1,110 @@
+package sortedbytes
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"math/big"
+)
+
+// maxBigIntBytes is the maximum length in bytes of the absolute value
+// of a big integer, since the length is encoded in one byte.
+const maxBigIntBytes = 255
+
+// AppendBigInt appends a *big.Int value to dst.
+//
+// Values whose absolute value fits in 64 bits are encoded in the same way as
+// AppendInt64 and AppendUint64 do, and larger values are encoded with
+// the FDB big integer type codes followed by the length byte, so that all
+// encoded values keep the order.
+//
+// AppendBigInt panics if the absolute value of value needs more than 255 bytes.
+//
+// You need to store the result of AppendBigInt like:
+//     dst = sortedbytes.AppendBigInt(dst, value)
+func AppendBigInt(dst []byte, value *big.Int) []byte {
+	switch value.Sign() {
+	case 0:
+		return append(dst, typeCodeIntZero)
+	case 1:
+		if value.IsUint64() {
+			return AppendUint64(dst, value.Uint64())
+		}
+		b := value.Bytes()
+		if len(b) > maxBigIntBytes {
+			panic("sortedbytes: big.Int value out of range")
+		}
+		dst = append(dst, typeCodePositiveBigInt, byte(len(b)))
+		return append(dst, b...)
+	default:
+		abs := new(big.Int).Neg(value)
+		if abs.IsUint64() {
+			var b [8]byte
+			binary.BigEndian.PutUint64(b[:], math.MaxUint64-abs.Uint64())
+			return append(append(dst, typeCodeNegativeInt64), b[:]...)
+		}
+		b := abs.Bytes()
+		if len(b) > maxBigIntBytes {
+			panic("sortedbytes: big.Int value out of range")
+		}
+		dst = append(dst, typeCodeNegativeBigInt, ^byte(len(b)))
+		for _, c := range b {
+			dst = append(dst, ^c)
+		}
+		return dst
+	}
+}
+
+// TakeBigInt takes a *big.Int value from b and returns it and the rest of b.
+//
+// TakeBigInt accepts any integer encoded by AppendInt32, AppendInt64,
+// AppendUint32, AppendUint64, AppendVarInt, or AppendBigInt.
+func TakeBigInt(b []byte) (value *big.Int, rest []byte, err error) {
+	var c byte
+	c, rest, err = takeTypeCode(b)
+	if err != nil {
+		return nil, b, newDecodeError(b, err, expectedBigInt)
+	}
+	value, rest, err = takeBigIntValue(c, rest)
+	if err != nil {
+		return nil, b, newDecodeError(b, err, expectedBigInt)
+	}
+	return value, rest, nil
+}
+
+func takeBigIntValue(c byte, b []byte) (value *big.Int, rest []byte, err error) {
+	var n int
+	switch {
+	case c == typeCodeIntZero:
+		return new(big.Int), b, nil
+	case c == typeCodePositiveBigInt:
+		if len(b) < 1 {
+			return nil, nil, io.ErrUnexpectedEOF
+		}
+		n = int(b[0])
+		b = b[1:]
+	case c == typeCodeNegativeBigInt:
+		if len(b) < 1 {
+			return nil, nil, io.ErrUnexpectedEOF
+		}
+		n = int(^b[0])
+		b = b[1:]
+	case typeCodeNegativeInt64 <= c && c <= typeCodePositiveInt64:
+		n = intPayloadLen(c)
+	default:
+		return nil, nil, ErrUnexpectedTypeCode
+	}
+	if len(b) < n {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+
+	if c > typeCodeIntZero {
+		return new(big.Int).SetBytes(b[:n]), b[n:], nil
+	}
+	abs := make([]byte, n)
+	for i := range abs {
+		abs[i] = ^b[i]
+	}
+	v := new(big.Int).SetBytes(abs)
+	return v.Neg(v), b[n:], nil
+}
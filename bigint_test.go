@@ -0,0 +1,146 @@
+package sortedbytes_test
+
+import (
+	"bytes"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/hnakamur/sortedbytes"
+)
+
+func mustParseBigInt(s string) *big.Int {
+	v, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		panic("invalid big.Int literal: " + s)
+	}
+	return v
+}
+
+func TestAppendBigInt(t *testing.T) {
+	t.Run("order", func(t *testing.T) {
+		testCases := []struct {
+			a, b *big.Int
+		}{
+			{a: mustParseBigInt("-0x1" + strings.Repeat("00", 254)), b: mustParseBigInt("-0x10000000000000000")},
+			{a: mustParseBigInt("-0x10000000000000001"), b: mustParseBigInt("-0x10000000000000000")},
+			{a: mustParseBigInt("-0x10000000000000000"), b: mustParseBigInt("-0xffffffffffffffff")},
+			{a: mustParseBigInt("-0xffffffffffffffff"), b: big.NewInt(math.MinInt64)},
+			{a: big.NewInt(-1), b: big.NewInt(0)},
+			{a: big.NewInt(0), b: big.NewInt(1)},
+			{a: new(big.Int).SetUint64(math.MaxUint64), b: mustParseBigInt("0x10000000000000000")},
+			{a: mustParseBigInt("0x10000000000000000"), b: mustParseBigInt("0x10000000000000001")},
+			{a: mustParseBigInt("0xffffffffffffffffff"), b: mustParseBigInt("0x1000000000000000000")},
+		}
+		for i, tc := range testCases {
+			a := sortedbytes.AppendBigInt([]byte(nil), tc.a)
+			b := sortedbytes.AppendBigInt([]byte(nil), tc.b)
+			if got, want := bytes.Compare(a, b), -1; got != want {
+				t.Errorf("case %d: compare result unmatch: got=%d, want=%d, a=0x%x, b=0x%x",
+					i, got, want, a, b)
+			}
+		}
+	})
+	t.Run("orderWithInt64", func(t *testing.T) {
+		testCases := []struct {
+			a int64
+			b *big.Int
+		}{
+			{a: math.MinInt64, b: big.NewInt(math.MinInt64 + 1)},
+			{a: -1, b: big.NewInt(0)},
+			{a: 1, b: big.NewInt(2)},
+			{a: math.MaxInt64, b: new(big.Int).SetUint64(math.MaxInt64 + 1)},
+			{a: math.MaxInt64, b: mustParseBigInt("0x10000000000000000")},
+		}
+		for i, tc := range testCases {
+			a := sortedbytes.AppendInt64([]byte(nil), tc.a)
+			b := sortedbytes.AppendBigInt([]byte(nil), tc.b)
+			if got, want := bytes.Compare(a, b), -1; got != want {
+				t.Errorf("case %d: compare result unmatch: got=%d, want=%d, a=0x%x, b=0x%x",
+					i, got, want, a, b)
+			}
+		}
+	})
+	t.Run("tooLarge", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("got no panic")
+			}
+		}()
+		sortedbytes.AppendBigInt([]byte(nil), mustParseBigInt("0x1"+strings.Repeat("00", 255)))
+	})
+	t.Run("sameAsInt64", func(t *testing.T) {
+		testCases := []int64{math.MinInt64, -1, 0, 1, math.MaxInt64}
+		for i, input := range testCases {
+			a := sortedbytes.AppendInt64([]byte(nil), input)
+			b := sortedbytes.AppendBigInt([]byte(nil), big.NewInt(input))
+			if !bytes.Equal(a, b) {
+				t.Errorf("case %d: encoded bytes unmatch: int64=0x%x, big.Int=0x%x", i, a, b)
+			}
+		}
+	})
+}
+
+func TestTakeBigInt(t *testing.T) {
+	t.Run("roundtrip", func(t *testing.T) {
+		testCases := []*big.Int{
+			mustParseBigInt("-0x1" + strings.Repeat("00", 254)),
+			mustParseBigInt("-0x10000000000000001"),
+			mustParseBigInt("-0x10000000000000000"),
+			mustParseBigInt("-0xffffffffffffffff"),
+			big.NewInt(math.MinInt64),
+			big.NewInt(-1),
+			big.NewInt(0),
+			big.NewInt(1),
+			big.NewInt(math.MaxInt64),
+			new(big.Int).SetUint64(math.MaxUint64),
+			mustParseBigInt("0x10000000000000000"),
+			mustParseBigInt("0x1" + strings.Repeat("00", 254)),
+		}
+		for i, input := range testCases {
+			b := sortedbytes.AppendBigInt([]byte(nil), input)
+			v, rest, err := sortedbytes.TakeBigInt(b)
+			if err != nil {
+				t.Errorf("case %d: got error: %s", i, err)
+				continue
+			}
+			if got, want := v, input; got.Cmp(want) != 0 {
+				t.Errorf("case %d: value unmatch: got=%s, want=%s", i, got, want)
+			}
+			if got, want := len(rest), 0; got != want {
+				t.Errorf("case %d: rest length unmatch: got=%d, want=%d", i, got, want)
+			}
+		}
+	})
+	t.Run("fromInt32", func(t *testing.T) {
+		testCases := []int32{math.MinInt32, -1, 0, 1, math.MaxInt32}
+		for i, input := range testCases {
+			b := sortedbytes.AppendInt32([]byte(nil), input)
+			v, _, err := sortedbytes.TakeBigInt(b)
+			if err != nil {
+				t.Errorf("case %d: got error: %s", i, err)
+				continue
+			}
+			if got, want := v, big.NewInt(int64(input)); got.Cmp(want) != 0 {
+				t.Errorf("case %d: value unmatch: got=%s, want=%s", i, got, want)
+			}
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		testCases := [][]byte{
+			[]byte("\x1d"),
+			[]byte("\x1d\x09\x01"),
+			[]byte("\x0b"),
+			[]byte("\x0b\xf6\xfe"),
+			[]byte("\x1c\x01\x02\x03\x04\x05\x06\x07"),
+			[]byte("\x02"),
+		}
+		for i, input := range testCases {
+			_, _, err := sortedbytes.TakeBigInt(input)
+			if err == nil {
+				t.Errorf("case %d: got no error", i)
+			}
+		}
+	})
+}
@@ -0,0 +1,159 @@
+package sortedbytes
+
+import (
+	"database/sql"
+	"math"
+)
+
+// The canonical NaN values are the quiet NaNs with the sign bit cleared
+// and no payload, which are returned by math.NaN for float64.
+const (
+	canonicalNaN64 = 0x7FF8_0000_0000_0000
+	canonicalNaN32 = 0x7FC0_0000
+)
+
+// AppendNullFloat64Canonical appends a NullFloat64 value to dst in
+// the canonical form.
+//
+// You need to store the result of AppendNullFloat64Canonical like:
+//     dst = sortedbytes.AppendNullFloat64Canonical(dst, value)
+func AppendNullFloat64Canonical(dst []byte, value sql.NullFloat64) []byte {
+	if value.Valid {
+		return AppendFloat64Canonical(dst, value.Float64)
+	}
+	return append(dst, typeCodeNull)
+}
+
+// AppendFloat64Canonical appends a float64 value to dst in the canonical
+// form.
+//
+// It is the same as AppendFloat64 except that -0 is encoded as +0 and
+// all NaNs are encoded as the canonical NaN, which is math.NaN(), so that
+// equal values and all NaNs are encoded to the same bytes. The canonical
+// NaN sorts after +Inf.
+//
+// You need to store the result of AppendFloat64Canonical like:
+//     dst = sortedbytes.AppendFloat64Canonical(dst, value)
+func AppendFloat64Canonical(dst []byte, value float64) []byte {
+	return AppendFloat64(dst, canonicalFloat64(value))
+}
+
+// TakeNullFloat64Canonical takes a NullFloat64 value encoded by
+// AppendNullFloat64Canonical from b and returns it and the rest of b.
+//
+// It returns an error wrapping ErrNonCanonical if the value is not in
+// the canonical form.
+func TakeNullFloat64Canonical(b []byte) (value sql.NullFloat64, rest []byte, err error) {
+	value, rest, err = TakeNullFloat64(b)
+	if err != nil {
+		return value, b, err
+	}
+	if value.Valid && !isCanonicalFloat64(value.Float64) {
+		return sql.NullFloat64{}, b, newNullDecodeError(b, ErrNonCanonical, expectedFloat64)
+	}
+	return value, rest, nil
+}
+
+// TakeFloat64Canonical takes a float64 value encoded by
+// AppendFloat64Canonical from b and returns it and the rest of b.
+//
+// It returns an error wrapping ErrNonCanonical if the value is not in
+// the canonical form.
+func TakeFloat64Canonical(b []byte) (value float64, rest []byte, err error) {
+	value, rest, err = TakeFloat64(b)
+	if err != nil {
+		return 0, b, err
+	}
+	if !isCanonicalFloat64(value) {
+		return 0, b, newDecodeError(b, ErrNonCanonical, expectedFloat64)
+	}
+	return value, rest, nil
+}
+
+// AppendNullFloat32Canonical appends a NullFloat32 value to dst in
+// the canonical form.
+//
+// You need to store the result of AppendNullFloat32Canonical like:
+//     dst = sortedbytes.AppendNullFloat32Canonical(dst, value)
+func AppendNullFloat32Canonical(dst []byte, value NullFloat32) []byte {
+	if value.Valid {
+		return AppendFloat32Canonical(dst, value.Float32)
+	}
+	return append(dst, typeCodeNull)
+}
+
+// AppendFloat32Canonical appends a float32 value to dst in the canonical
+// form.
+//
+// It is the same as AppendFloat32 except that -0 is encoded as +0 and
+// all NaNs are encoded as the canonical NaN, which is float32(math.NaN()),
+// so that equal values and all NaNs are encoded to the same bytes.
+// The canonical NaN sorts after +Inf.
+//
+// You need to store the result of AppendFloat32Canonical like:
+//     dst = sortedbytes.AppendFloat32Canonical(dst, value)
+func AppendFloat32Canonical(dst []byte, value float32) []byte {
+	return AppendFloat32(dst, canonicalFloat32(value))
+}
+
+// TakeNullFloat32Canonical takes a NullFloat32 value encoded by
+// AppendNullFloat32Canonical from b and returns it and the rest of b.
+//
+// It returns an error wrapping ErrNonCanonical if the value is not in
+// the canonical form.
+func TakeNullFloat32Canonical(b []byte) (value NullFloat32, rest []byte, err error) {
+	value, rest, err = TakeNullFloat32(b)
+	if err != nil {
+		return value, b, err
+	}
+	if value.Valid && !isCanonicalFloat32(value.Float32) {
+		return NullFloat32{}, b, newNullDecodeError(b, ErrNonCanonical, expectedFloat32)
+	}
+	return value, rest, nil
+}
+
+// TakeFloat32Canonical takes a float32 value encoded by
+// AppendFloat32Canonical from b and returns it and the rest of b.
+//
+// It returns an error wrapping ErrNonCanonical if the value is not in
+// the canonical form.
+func TakeFloat32Canonical(b []byte) (value float32, rest []byte, err error) {
+	value, rest, err = TakeFloat32(b)
+	if err != nil {
+		return 0, b, err
+	}
+	if !isCanonicalFloat32(value) {
+		return 0, b, newDecodeError(b, ErrNonCanonical, expectedFloat32)
+	}
+	return value, rest, nil
+}
+
+func canonicalFloat64(v float64) float64 {
+	switch {
+	case v == 0:
+		return 0
+	case math.IsNaN(v):
+		return math.Float64frombits(canonicalNaN64)
+	default:
+		return v
+	}
+}
+
+func isCanonicalFloat64(v float64) bool {
+	return math.Float64bits(v) == math.Float64bits(canonicalFloat64(v))
+}
+
+func canonicalFloat32(v float32) float32 {
+	switch {
+	case v == 0:
+		return 0
+	case v != v: // NaN
+		return math.Float32frombits(canonicalNaN32)
+	default:
+		return v
+	}
+}
+
+func isCanonicalFloat32(v float32) bool {
+	return math.Float32bits(v) == math.Float32bits(canonicalFloat32(v))
+}
@@ -0,0 +1,167 @@
+package sortedbytes_test
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/hnakamur/sortedbytes"
+)
+
+func TestAppendFloat64Canonical(t *testing.T) {
+	t.Run("same", func(t *testing.T) {
+		testCases := []struct {
+			a, b float64
+		}{
+			{a: 0, b: math.Copysign(0, -1)},
+			{a: math.NaN(), b: math.Float64frombits(0x7FF0_0000_0000_0001)},
+			{a: math.NaN(), b: math.Float64frombits(0xFFF8_0000_0000_0000)},
+			{a: math.NaN(), b: math.Float64frombits(0xFFFF_FFFF_FFFF_FFFF)},
+		}
+		for i, tc := range testCases {
+			a := sortedbytes.AppendFloat64Canonical([]byte(nil), tc.a)
+			b := sortedbytes.AppendFloat64Canonical([]byte(nil), tc.b)
+			if !bytes.Equal(a, b) {
+				t.Errorf("case %d: encoded bytes unmatch: a=0x%x, b=0x%x", i, a, b)
+			}
+		}
+	})
+	t.Run("order", func(t *testing.T) {
+		testCases := []float64{
+			math.Inf(-1),
+			-1,
+			math.Copysign(0, -1),
+			1,
+			math.Inf(1),
+			math.Float64frombits(0xFFFF_FFFF_FFFF_FFFF),
+		}
+		for i := 1; i < len(testCases); i++ {
+			a := sortedbytes.AppendFloat64Canonical([]byte(nil), testCases[i-1])
+			b := sortedbytes.AppendFloat64Canonical([]byte(nil), testCases[i])
+			if got, want := bytes.Compare(a, b), -1; got != want {
+				t.Errorf("case %d: compare result unmatch: got=%d, want=%d, a=0x%x, b=0x%x",
+					i, got, want, a, b)
+			}
+		}
+	})
+}
+
+func TestTakeFloat64Canonical(t *testing.T) {
+	t.Run("roundtrip", func(t *testing.T) {
+		testCases := []float64{math.Inf(-1), -1.5, math.Copysign(0, -1), 0, 1.5, math.Inf(1), math.NaN()}
+		for i, input := range testCases {
+			b := sortedbytes.AppendFloat64Canonical([]byte(nil), input)
+			v, rest, err := sortedbytes.TakeFloat64Canonical(b)
+			if err != nil {
+				t.Errorf("case %d: got error: %s", i, err)
+			}
+			if got, want := math.Float64bits(v), math.Float64bits(input); got != want && input != 0 && !math.IsNaN(input) {
+				t.Errorf("case %d: value unmatch: got=%v, want=%v", i, v, input)
+			}
+			if got, want := len(rest), 0; got != want {
+				t.Errorf("case %d: rest length unmatch: got=%d, want=%d", i, got, want)
+			}
+
+			n, _, err := sortedbytes.TakeNullFloat64Canonical(b)
+			if err != nil {
+				t.Errorf("case %d: null: got error: %s", i, err)
+			}
+			if !n.Valid || math.Float64bits(n.Float64) != math.Float64bits(v) {
+				t.Errorf("case %d: null: value unmatch: got=%v, want=%v", i, n, v)
+			}
+		}
+	})
+	t.Run("nonCanonical", func(t *testing.T) {
+		testCases := [][]byte{
+			sortedbytes.AppendFloat64([]byte(nil), math.Copysign(0, -1)),
+			sortedbytes.AppendFloat64([]byte(nil), math.Float64frombits(0xFFF8_0000_0000_0000)),
+			sortedbytes.AppendFloat64([]byte(nil), math.Float64frombits(0x7FF0_0000_0000_0001)),
+		}
+		for i, input := range testCases {
+			v, rest, err := sortedbytes.TakeFloat64Canonical(input)
+			if !errors.Is(err, sortedbytes.ErrNonCanonical) {
+				t.Errorf("case %d: got error %v, want ErrNonCanonical", i, err)
+			}
+			if v != 0 || !bytes.Equal(rest, input) {
+				t.Errorf("case %d: unexpected result on error: v=%v, rest=0x%x", i, v, rest)
+			}
+			if _, _, err := sortedbytes.TakeNullFloat64Canonical(input); !errors.Is(err, sortedbytes.ErrNonCanonical) {
+				t.Errorf("case %d: null: got error %v, want ErrNonCanonical", i, err)
+			}
+		}
+	})
+	t.Run("null", func(t *testing.T) {
+		b := sortedbytes.AppendNullFloat64Canonical([]byte(nil), sql.NullFloat64{})
+		v, rest, err := sortedbytes.TakeNullFloat64Canonical(b)
+		if err != nil || v.Valid || len(rest) != 0 {
+			t.Errorf("unexpected result: v=%v, rest=0x%x, err=%v", v, rest, err)
+		}
+	})
+}
+
+func TestAppendFloat32Canonical(t *testing.T) {
+	nan := float32(math.NaN())
+	testCases := []struct {
+		a, b float32
+	}{
+		{a: 0, b: float32(math.Copysign(0, -1))},
+		{a: nan, b: math.Float32frombits(0x7F80_0001)},
+		{a: nan, b: math.Float32frombits(0xFFFF_FFFF)},
+	}
+	for i, tc := range testCases {
+		a := sortedbytes.AppendFloat32Canonical([]byte(nil), tc.a)
+		b := sortedbytes.AppendFloat32Canonical([]byte(nil), tc.b)
+		if !bytes.Equal(a, b) {
+			t.Errorf("case %d: encoded bytes unmatch: a=0x%x, b=0x%x", i, a, b)
+		}
+	}
+	a := sortedbytes.AppendFloat32Canonical([]byte(nil), float32(math.Inf(1)))
+	b := sortedbytes.AppendFloat32Canonical([]byte(nil), nan)
+	if got, want := bytes.Compare(a, b), -1; got != want {
+		t.Errorf("compare result unmatch: got=%d, want=%d, a=0x%x, b=0x%x", got, want, a, b)
+	}
+}
+
+func TestTakeFloat32Canonical(t *testing.T) {
+	t.Run("roundtrip", func(t *testing.T) {
+		testCases := []sortedbytes.NullFloat32{
+			{},
+			{Valid: true, Float32: -1.5},
+			{Valid: true, Float32: 0},
+			{Valid: true, Float32: float32(math.Inf(1))},
+		}
+		for i, input := range testCases {
+			b := sortedbytes.AppendNullFloat32Canonical([]byte(nil), input)
+			v, rest, err := sortedbytes.TakeNullFloat32Canonical(b)
+			if err != nil {
+				t.Errorf("case %d: got error: %s", i, err)
+			}
+			if v != input {
+				t.Errorf("case %d: value unmatch: got=%v, want=%v", i, v, input)
+			}
+			if got, want := len(rest), 0; got != want {
+				t.Errorf("case %d: rest length unmatch: got=%d, want=%d", i, got, want)
+			}
+		}
+		b := sortedbytes.AppendFloat32Canonical([]byte(nil), float32(math.NaN()))
+		if v, _, err := sortedbytes.TakeFloat32Canonical(b); err != nil || v == v {
+			t.Errorf("unexpected result for NaN: v=%v, err=%v", v, err)
+		}
+	})
+	t.Run("nonCanonical", func(t *testing.T) {
+		testCases := [][]byte{
+			sortedbytes.AppendFloat32([]byte(nil), float32(math.Copysign(0, -1))),
+			sortedbytes.AppendFloat32([]byte(nil), math.Float32frombits(0xFFC0_0000)),
+		}
+		for i, input := range testCases {
+			if _, _, err := sortedbytes.TakeFloat32Canonical(input); !errors.Is(err, sortedbytes.ErrNonCanonical) {
+				t.Errorf("case %d: got error %v, want ErrNonCanonical", i, err)
+			}
+			if _, _, err := sortedbytes.TakeNullFloat32Canonical(input); !errors.Is(err, sortedbytes.ErrNonCanonical) {
+				t.Errorf("case %d: null: got error %v, want ErrNonCanonical", i, err)
+			}
+		}
+	})
+}
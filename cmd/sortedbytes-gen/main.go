@@ -0,0 +1,456 @@
+// Command sortedbytes-gen generates AppendKey and TakeKey methods for
+// a struct type, which encode and decode the struct as a composite key
+// with the functions in the sortedbytes package without reflection.
+//
+// Usage:
+//     sortedbytes-gen -type=Key [-output=key_sortedbytes.go] [file.go ...]
+//
+// It is intended to be used with go generate like:
+//     //go:generate sortedbytes-gen -type=Key
+//
+// When no files are given, the file named by the GOFILE environment
+// variable which is set by go generate is read.
+//
+// The generated methods are
+//     func (k *Key) AppendKey(dst []byte) []byte
+//     func (k *Key) TakeKey(b []byte) (rest []byte, err error)
+//
+// When TakeKey fails, the returned error is a *sortedbytes.DecodeError
+// whose Offset is the offset in b.
+//
+// Fields are encoded in the same way as sortedbytes.Marshal including
+// the "sortedbytes" field tags, except that types are resolved by their
+// names in the source, so the supported field types are bool, int, int8,
+// int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32,
+// float64, string, []byte, *big.Int, time.Time, sql.NullBool,
+// sql.NullInt32, sql.NullInt64, sql.NullFloat64, sql.NullString,
+// sql.NullTime, sortedbytes.NullUint32, sortedbytes.NullUint64,
+// sortedbytes.NullFloat32, sortedbytes.NullBytes, sortedbytes.UUID, and
+// sortedbytes.NullUUID, and the types declared in the package of the struct
+// whose underlying types are one of bool, the integer types, float32,
+// float64, string, and []byte, like
+//     type UserID int64
+//
+// Types declared in the other packages are not resolved even if Marshal
+// supports them. Embedded fields are not supported.
+//
+// Like Unmarshal, TakeKey returns an error wrapping
+// sortedbytes.ErrValueOutOfRange when a decoded integer does not fit in
+// the field type. Unlike Marshal, AppendKey panics when a *big.Int field is
+// nil or its absolute value needs more than 255 bytes, since it does not
+// return an error.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hnakamur/sortedbytes/internal/structtag"
+)
+
+const sortedbytesPath = "github.com/hnakamur/sortedbytes"
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("sortedbytes-gen: ")
+
+	typeName := flag.String("type", "", "struct type name; must be set")
+	output := flag.String("output", "", "output file name; default <type>_sortedbytes.go in the directory of the first file")
+	flag.Parse()
+	if *typeName == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	files := flag.Args()
+	if len(files) == 0 {
+		gofile := os.Getenv("GOFILE")
+		if gofile == "" {
+			log.Fatal("no files given and GOFILE is not set")
+		}
+		files = []string{gofile}
+	}
+
+	src, err := generateFromFiles(files, *typeName)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	outName := *output
+	if outName == "" {
+		outName = filepath.Join(filepath.Dir(files[0]), strings.ToLower(*typeName)+"_sortedbytes.go")
+	}
+	if err := ioutil.WriteFile(outName, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func generateFromFiles(filenames []string, typeName string) ([]byte, error) {
+	fset := token.NewFileSet()
+	for _, filename := range filenames {
+		f, err := parser.ParseFile(fset, filename, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		st := findStruct(f, typeName)
+		if st == nil {
+			continue
+		}
+		declared, err := packageTypes(fset, filepath.Dir(filename), f)
+		if err != nil {
+			return nil, err
+		}
+		return generate(f, typeName, st, declared)
+	}
+	return nil, fmt.Errorf("struct type %s not found", typeName)
+}
+
+// packageTypes returns the map from the names of the types declared in
+// the package of f to their type specs. The package is the non-test files
+// in dir whose package name is the same as f.
+func packageTypes(fset *token.FileSet, dir string, f *ast.File) (map[string]*ast.TypeSpec, error) {
+	declared := make(map[string]*ast.TypeSpec)
+	addFileTypes(declared, f)
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+	if pkg, ok := pkgs[f.Name.Name]; ok {
+		for _, pf := range pkg.Files {
+			addFileTypes(declared, pf)
+		}
+	}
+	return declared, nil
+}
+
+func addFileTypes(declared map[string]*ast.TypeSpec, f *ast.File) {
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts := spec.(*ast.TypeSpec)
+			declared[ts.Name.Name] = ts
+		}
+	}
+}
+
+func findStruct(f *ast.File, typeName string) *ast.StructType {
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if ts.Name.Name != typeName {
+				continue
+			}
+			if st, ok := ts.Type.(*ast.StructType); ok {
+				return st
+			}
+		}
+	}
+	return nil
+}
+
+// field is a struct field to be encoded.
+type field struct {
+	name     string
+	position int
+	desc     bool
+	kind     fieldKind
+}
+
+// fieldKind describes how to encode and decode a field type.
+type fieldKind struct {
+	// fn is the suffix of the Append and Take function names.
+	fn string
+	// conv is the type of the argument of the Append function and
+	// the result of the Take function when it differs from the field type
+	// typ.
+	conv string
+	typ  string
+	// narrow is true if a value of conv may not fit in typ, so TakeKey
+	// must check the range.
+	narrow bool
+}
+
+var basicKinds = map[string]fieldKind{
+	"bool":    {fn: "Bool"},
+	"int":     {fn: "Int64", conv: "int64", typ: "int", narrow: true},
+	"int8":    {fn: "Int32", conv: "int32", typ: "int8", narrow: true},
+	"int16":   {fn: "Int32", conv: "int32", typ: "int16", narrow: true},
+	"int32":   {fn: "Int32"},
+	"rune":    {fn: "Int32"},
+	"int64":   {fn: "Int64"},
+	"uint":    {fn: "Uint64", conv: "uint64", typ: "uint", narrow: true},
+	"uint8":   {fn: "Uint32", conv: "uint32", typ: "uint8", narrow: true},
+	"byte":    {fn: "Uint32", conv: "uint32", typ: "byte", narrow: true},
+	"uint16":  {fn: "Uint32", conv: "uint32", typ: "uint16", narrow: true},
+	"uint32":  {fn: "Uint32"},
+	"uint64":  {fn: "Uint64"},
+	"float32": {fn: "Float32"},
+	"float64": {fn: "Float64"},
+	"string":  {fn: "String"},
+}
+
+// The maximum length of a chain of declared types like
+//     type A B
+//     type B int64
+const maxTypeDepth = 8
+
+var qualifiedKinds = map[string]fieldKind{
+	"database/sql.NullBool":          {fn: "NullBool"},
+	"database/sql.NullInt32":         {fn: "NullInt32"},
+	"database/sql.NullInt64":         {fn: "NullInt64"},
+	"database/sql.NullFloat64":       {fn: "NullFloat64"},
+	"database/sql.NullString":        {fn: "NullString"},
+	"database/sql.NullTime":          {fn: "NullTime"},
+	"time.Time":                      {fn: "Time"},
+	sortedbytesPath + ".NullUint32":  {fn: "NullUint32"},
+	sortedbytesPath + ".NullUint64":  {fn: "NullUint64"},
+	sortedbytesPath + ".NullBytes":   {fn: "NullBytes"},
+	sortedbytesPath + ".UUID":        {fn: "UUID"},
+	sortedbytesPath + ".NullUUID":    {fn: "NullUUID"},
+	sortedbytesPath + ".NullFloat32": {fn: "NullFloat32"},
+}
+
+func generate(f *ast.File, typeName string, st *ast.StructType, declared map[string]*ast.TypeSpec) ([]byte, error) {
+	r := &resolver{imports: fileImports(f), declared: declared}
+	var fields []field
+	withPosition := 0
+	for _, fl := range st.Fields.List {
+		var tagValue string
+		if fl.Tag != nil {
+			s, err := strconv.Unquote(fl.Tag.Value)
+			if err != nil {
+				return nil, err
+			}
+			tagValue = reflect.StructTag(s).Get(structtag.Name)
+		}
+		tag, err := structtag.Parse(tagValue)
+		if err != nil {
+			return nil, fmt.Errorf("type %s: %w", typeName, err)
+		}
+		if tag.Skip {
+			continue
+		}
+		if len(fl.Names) == 0 {
+			return nil, fmt.Errorf("type %s: embedded field %s is not supported", typeName, embeddedName(fl.Type))
+		}
+		for _, name := range fl.Names {
+			if !name.IsExported() {
+				continue
+			}
+			kind, ok := r.kindForExpr(fl.Type, 0)
+			if !ok {
+				return nil, fmt.Errorf("field %s: unsupported type", name.Name)
+			}
+			if tag.HasPosition {
+				withPosition++
+			}
+			fields = append(fields, field{
+				name:     name.Name,
+				position: tag.Position,
+				desc:     tag.Desc,
+				kind:     kind,
+			})
+		}
+	}
+
+	if withPosition > 0 {
+		if withPosition != len(fields) {
+			return nil, fmt.Errorf("type %s: either all or none of fields must have positions", typeName)
+		}
+		sort.SliceStable(fields, func(i, j int) bool {
+			return fields[i].position < fields[j].position
+		})
+		for i := 1; i < len(fields); i++ {
+			if fields[i-1].position == fields[i].position {
+				return nil, fmt.Errorf("type %s: duplicate position %d for fields %s and %s",
+					typeName, fields[i].position, fields[i-1].name, fields[i].name)
+			}
+		}
+	}
+
+	return render(f.Name.Name, typeName, fields)
+}
+
+// fileImports returns the map from the local package names to the import
+// paths in f.
+func fileImports(f *ast.File) map[string]string {
+	imports := make(map[string]string)
+	for _, spec := range f.Imports {
+		p, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := path.Base(p)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		imports[name] = p
+	}
+	return imports
+}
+
+// embeddedName returns the name of the embedded field of type expr.
+func embeddedName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return embeddedName(e.X)
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	case *ast.Ident:
+		return e.Name
+	}
+	return fmt.Sprintf("%T", expr)
+}
+
+// resolver resolves the kinds of field types in a file.
+type resolver struct {
+	imports  map[string]string        // from the local package names to the paths
+	declared map[string]*ast.TypeSpec // the types declared in the package
+}
+
+func (r *resolver) kindForExpr(expr ast.Expr, depth int) (fieldKind, bool) {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if ts, ok := r.declared[e.Name]; ok {
+			return r.kindForDeclared(ts, depth)
+		}
+		kind, ok := basicKinds[e.Name]
+		return kind, ok
+	case *ast.ArrayType:
+		if ident, ok := e.Elt.(*ast.Ident); ok && e.Len == nil && ident.Name == "byte" {
+			return fieldKind{fn: "Bytes"}, true
+		}
+	case *ast.SelectorExpr:
+		if name, ok := qualifiedName(e, r.imports); ok {
+			kind, ok := qualifiedKinds[name]
+			return kind, ok
+		}
+	case *ast.StarExpr:
+		if sel, ok := e.X.(*ast.SelectorExpr); ok {
+			if name, ok := qualifiedName(sel, r.imports); ok && name == "math/big.Int" {
+				return fieldKind{fn: "BigInt"}, true
+			}
+		}
+	}
+	return fieldKind{}, false
+}
+
+// kindForDeclared returns the kind of the type declared by ts if its
+// underlying type is a basic type or []byte.
+func (r *resolver) kindForDeclared(ts *ast.TypeSpec, depth int) (fieldKind, bool) {
+	if depth >= maxTypeDepth {
+		return fieldKind{}, false
+	}
+	switch ts.Type.(type) {
+	case *ast.Ident, *ast.ArrayType:
+	default:
+		// Declared types whose underlying types are qualified types like
+		// time.Time are not supported by Marshal.
+		return fieldKind{}, false
+	}
+	kind, ok := r.kindForExpr(ts.Type, depth+1)
+	if !ok {
+		return fieldKind{}, false
+	}
+	if ts.Assign.IsValid() {
+		// An alias is the same type as the aliased type.
+		return kind, true
+	}
+	if kind.conv == "" {
+		kind.conv = types.ExprString(ts.Type)
+	}
+	kind.typ = ts.Name.Name
+	return kind, true
+}
+
+func qualifiedName(sel *ast.SelectorExpr, imports map[string]string) (string, bool) {
+	x, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return "", false
+	}
+	p, ok := imports[x.Name]
+	if !ok {
+		return "", false
+	}
+	return p + "." + sel.Sel.Name, true
+}
+
+func render(pkgName, typeName string, fields []field) ([]byte, error) {
+	if len(fields) == 0 {
+		return nil, errors.New("no fields to encode")
+	}
+	recv := strings.ToLower(typeName[:1])
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by sortedbytes-gen; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", pkgName)
+	fmt.Fprintf(&buf, "import %q\n\n", sortedbytesPath)
+
+	fmt.Fprintf(&buf, "// AppendKey appends the encoded key of %s to dst.\n", recv)
+	fmt.Fprintf(&buf, "func (%s *%s) AppendKey(dst []byte) []byte {\n", recv, typeName)
+	for _, f := range fields {
+		arg := recv + "." + f.name
+		if f.kind.conv != "" {
+			arg = f.kind.conv + "(" + arg + ")"
+		}
+		fmt.Fprintf(&buf, "dst = sortedbytes.Append%s(dst, %s)\n", f.funcSuffix(), arg)
+	}
+	fmt.Fprintf(&buf, "return dst\n}\n\n")
+
+	fmt.Fprintf(&buf, "// TakeKey takes the encoded key from b into %s and returns the rest of b.\n", recv)
+	fmt.Fprintf(&buf, "func (%s *%s) TakeKey(b []byte) (rest []byte, err error) {\n", recv, typeName)
+	fmt.Fprintf(&buf, "rest = b\n")
+	for _, f := range fields {
+		if f.kind.conv == "" {
+			fmt.Fprintf(&buf, "if %s.%s, rest, err = sortedbytes.Take%s(rest); err != nil {\nreturn b, sortedbytes.AddOffset(err, len(b)-len(rest))\n}\n",
+				recv, f.name, f.funcSuffix())
+			continue
+		}
+		fmt.Fprintf(&buf, "{\n")
+		if f.kind.narrow {
+			fmt.Fprintf(&buf, "off := len(b) - len(rest)\n")
+		}
+		fmt.Fprintf(&buf, "var v %s\nif v, rest, err = sortedbytes.Take%s(rest); err != nil {\nreturn b, sortedbytes.AddOffset(err, len(b)-len(rest))\n}\n",
+			f.kind.conv, f.funcSuffix())
+		if f.kind.narrow {
+			fmt.Fprintf(&buf, "if %s(%s(v)) != v {\nreturn b, &sortedbytes.DecodeError{Offset: off, TypeCode: b[off], Err: sortedbytes.ErrValueOutOfRange}\n}\n",
+				f.kind.conv, f.kind.typ)
+		}
+		fmt.Fprintf(&buf, "%s.%s = %s(v)\n}\n", recv, f.name, f.kind.typ)
+	}
+	fmt.Fprintf(&buf, "return rest, nil\n}\n")
+
+	return format.Source(buf.Bytes())
+}
+
+func (f field) funcSuffix() string {
+	if f.desc {
+		return f.kind.fn + "Desc"
+	}
+	return f.kind.fn
+}
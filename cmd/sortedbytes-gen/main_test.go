@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateFromFiles(t *testing.T) {
+	t.Run("golden", func(t *testing.T) {
+		dir := filepath.Join("..", "..", "internal", "gentest")
+		for _, typeName := range []string{"Key", "SmallKey"} {
+			got, err := generateFromFiles([]string{filepath.Join(dir, "key.go")}, typeName)
+			if err != nil {
+				t.Fatalf("type %s: got error: %s", typeName, err)
+			}
+			want, err := ioutil.ReadFile(filepath.Join(dir, strings.ToLower(typeName)+"_sortedbytes.go"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("type %s: generated code unmatch; run go generate in internal/gentest:\n got=%s\nwant=%s", typeName, got, want)
+			}
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		testCases := []string{
+			"package p\ntype Other struct{ A int }\n",
+			"package p\ntype Key struct{ A []int }\n",
+			"package p\ntype Key struct{ a int }\n",
+			"package p\ntype Key struct{ A int `sortedbytes:\"x\"` }\n",
+			"package p\ntype Key struct {\nA int `sortedbytes:\"1\"`\nB int\n}\n",
+			"package p\ntype Key struct {\nA int `sortedbytes:\"1\"`\nB int `sortedbytes:\"1\"`\n}\n",
+			"package p\nimport \"time\"\ntype Key struct{ A time.Duration }\n",
+			"package p\ntype ID int64\ntype Key struct{ ID }\n",
+			"package p\nimport \"time\"\ntype T time.Time\ntype Key struct{ A T }\n",
+			"package p\ntype A struct{ B int }\ntype Key struct{ A A }\n",
+		}
+		dir, err := ioutil.TempDir("", "sortedbytes-gen")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+		for i, src := range testCases {
+			filename := filepath.Join(dir, "key.go")
+			if err := ioutil.WriteFile(filename, []byte(src), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := generateFromFiles([]string{filename}, "Key"); err == nil {
+				t.Errorf("case %d: got no error", i)
+			}
+		}
+	})
+}
@@ -0,0 +1,689 @@
+package sortedbytes
+
+// The descending order encoding of a value is the bitwise inversion of
+// the ascending order encoding, so that the byte-wise comparison yields the
+// reverse order.
+//
+// There are two exceptions. One is that a null value is encoded as
+// typeCodeNull in the descending order too, so that a null value never
+// starts with 0xFF which would be taken for the escape byte after 0x00 at
+// the end of a preceding string or byte string. It means null values sort
+// before non-null values in both orders.
+//
+// The other is that a string or a byte string is terminated with 0xFF 0xFF
+// in the descending order, instead of 0xFF which is the inversion of the
+// terminator 0x00. The inverted escape sequence for 0x00 is 0xFF 0x00, so
+// a longer value sorts before its prefix as it should be.
+
+import (
+	"database/sql"
+	"io"
+	"math/big"
+	"time"
+)
+
+// AppendNullStringDesc appends a sql.NullString value to dst in the descending order.
+//
+// You need to store the result of AppendNullStringDesc like:
+//     dst = sortedbytes.AppendNullStringDesc(dst, value)
+func AppendNullStringDesc(dst []byte, value sql.NullString) []byte {
+	if value.Valid {
+		return AppendStringDesc(dst, value.String)
+	}
+	return append(dst, typeCodeNull)
+}
+
+// AppendStringDesc appends a string value to dst in the descending order.
+//
+// You need to store the result of AppendStringDesc like:
+//     dst = sortedbytes.AppendStringDesc(dst, value)
+func AppendStringDesc(dst []byte, value string) []byte {
+	dst = append(dst, ^byte(typeCodeUTF8String))
+	for i := 0; i < len(value); i++ {
+		if c := value[i]; c == '\x00' {
+			dst = append(dst, '\xFF', '\x00')
+		} else {
+			dst = append(dst, ^c)
+		}
+	}
+	return append(dst, '\xFF', '\xFF')
+}
+
+// TakeNullStringDesc takes a sql.NullString value encoded by AppendNullStringDesc
+// from b and returns it and the rest of b.
+func TakeNullStringDesc(b []byte) (value sql.NullString, rest []byte, err error) {
+	if len(b) > 0 && b[0] == typeCodeNull {
+		return value, b[1:], nil
+	}
+	var v string
+	v, rest, err = TakeStringDesc(b)
+	if err != nil {
+		return value, b, withNullExpected(err)
+	}
+	return sql.NullString{Valid: true, String: v}, rest, nil
+}
+
+// TakeStringDesc takes a string value encoded by AppendStringDesc from b
+// and returns it and the rest of b.
+func TakeStringDesc(b []byte) (value string, rest []byte, err error) {
+	rest, err = expectTypeCode(b, ^byte(typeCodeUTF8String))
+	if err != nil {
+		return "", b, newDecodeError(b, err, expectedStringDesc)
+	}
+	var v []byte
+	v, rest, err = takeDescEscapedValue(rest)
+	if err != nil {
+		return "", b, newDecodeError(b, err, expectedStringDesc)
+	}
+	return string(v), rest, nil
+}
+
+// AppendNullBytesDesc appends a NullBytes value to dst in the descending order.
+//
+// You need to store the result of AppendNullBytesDesc like:
+//     dst = sortedbytes.AppendNullBytesDesc(dst, value)
+func AppendNullBytesDesc(dst []byte, value NullBytes) []byte {
+	if value.Valid {
+		return AppendBytesDesc(dst, value.Bytes)
+	}
+	return append(dst, typeCodeNull)
+}
+
+// AppendBytesDesc appends a byte string value to dst in the descending order.
+//
+// You need to store the result of AppendBytesDesc like:
+//     dst = sortedbytes.AppendBytesDesc(dst, value)
+func AppendBytesDesc(dst []byte, value []byte) []byte {
+	dst = append(dst, ^byte(typeCodeByteString))
+	for _, c := range value {
+		if c == '\x00' {
+			dst = append(dst, '\xFF', '\x00')
+		} else {
+			dst = append(dst, ^c)
+		}
+	}
+	return append(dst, '\xFF', '\xFF')
+}
+
+// TakeNullBytesDesc takes a NullBytes value encoded by AppendNullBytesDesc
+// from b and returns it and the rest of b.
+func TakeNullBytesDesc(b []byte) (value NullBytes, rest []byte, err error) {
+	if len(b) > 0 && b[0] == typeCodeNull {
+		return value, b[1:], nil
+	}
+	var v []byte
+	v, rest, err = TakeBytesDesc(b)
+	if err != nil {
+		return value, b, withNullExpected(err)
+	}
+	return NullBytes{Valid: true, Bytes: v}, rest, nil
+}
+
+// TakeBytesDesc takes a byte string value encoded by AppendBytesDesc from b
+// and returns it and the rest of b.
+func TakeBytesDesc(b []byte) (value []byte, rest []byte, err error) {
+	rest, err = expectTypeCode(b, ^byte(typeCodeByteString))
+	if err != nil {
+		return nil, b, newDecodeError(b, err, expectedBytesDesc)
+	}
+	value, rest, err = takeDescEscapedValue(rest)
+	if err != nil {
+		return nil, b, newDecodeError(b, err, expectedBytesDesc)
+	}
+	return value, rest, nil
+}
+
+// takeDescEscapedValue takes a value escaped by AppendStringDesc or
+// AppendBytesDesc from src and returns it and the rest of src.
+func takeDescEscapedValue(src []byte) (value []byte, rest []byte, err error) {
+	value = []byte{}
+	for i := 0; i < len(src); i++ {
+		if c := src[i]; c != '\xFF' {
+			value = append(value, ^c)
+			continue
+		}
+
+		if i+1 >= len(src) {
+			break
+		}
+		switch src[i+1] {
+		case '\x00':
+			value = append(value, '\x00')
+			i++
+		case '\xFF':
+			return value, src[i+2:], nil
+		default:
+			return nil, nil, ErrInvalidEscape
+		}
+	}
+	return nil, nil, io.ErrUnexpectedEOF
+}
+
+// AppendNullInt32Desc appends a NullInt32 value to dst in the descending order.
+//
+// You need to store the result of AppendNullInt32Desc like:
+//     dst = sortedbytes.AppendNullInt32Desc(dst, value)
+func AppendNullInt32Desc(dst []byte, value sql.NullInt32) []byte {
+	if value.Valid {
+		return AppendInt32Desc(dst, value.Int32)
+	}
+	return append(dst, typeCodeNull)
+}
+
+// AppendInt32Desc appends an int32 value to dst in the descending order.
+// The result is the bitwise inversion of the result of AppendInt32.
+//
+// You need to store the result of AppendInt32Desc like:
+//     dst = sortedbytes.AppendInt32Desc(dst, value)
+func AppendInt32Desc(dst []byte, value int32) []byte {
+	start := len(dst)
+	dst = AppendInt32(dst, value)
+	invertBytes(dst[start:])
+	return dst
+}
+
+// TakeNullInt32Desc takes a NullInt32 value encoded by AppendNullInt32Desc from b
+// and returns it and the rest of b.
+func TakeNullInt32Desc(b []byte) (value sql.NullInt32, rest []byte, err error) {
+	if len(b) > 0 && b[0] == typeCodeNull {
+		return value, b[1:], nil
+	}
+	var v int32
+	v, rest, err = TakeInt32Desc(b)
+	if err != nil {
+		return value, b, withNullExpected(err)
+	}
+	return sql.NullInt32{Valid: true, Int32: v}, rest, nil
+}
+
+// TakeInt32Desc takes an int32 value encoded by AppendInt32Desc from b
+// and returns it and the rest of b.
+func TakeInt32Desc(b []byte) (value int32, rest []byte, err error) {
+	var buf [5]byte
+	a := invertPrefix(buf[:], b)
+	value, rest, err = TakeInt32(a)
+	if err != nil {
+		return 0, b, invertDecodeError(b, err)
+	}
+	return value, b[len(a)-len(rest):], nil
+}
+
+// AppendNullInt64Desc appends a NullInt64 value to dst in the descending order.
+//
+// You need to store the result of AppendNullInt64Desc like:
+//     dst = sortedbytes.AppendNullInt64Desc(dst, value)
+func AppendNullInt64Desc(dst []byte, value sql.NullInt64) []byte {
+	if value.Valid {
+		return AppendInt64Desc(dst, value.Int64)
+	}
+	return append(dst, typeCodeNull)
+}
+
+// AppendInt64Desc appends an int64 value to dst in the descending order.
+// The result is the bitwise inversion of the result of AppendInt64.
+//
+// You need to store the result of AppendInt64Desc like:
+//     dst = sortedbytes.AppendInt64Desc(dst, value)
+func AppendInt64Desc(dst []byte, value int64) []byte {
+	start := len(dst)
+	dst = AppendInt64(dst, value)
+	invertBytes(dst[start:])
+	return dst
+}
+
+// TakeNullInt64Desc takes a NullInt64 value encoded by AppendNullInt64Desc from b
+// and returns it and the rest of b.
+func TakeNullInt64Desc(b []byte) (value sql.NullInt64, rest []byte, err error) {
+	if len(b) > 0 && b[0] == typeCodeNull {
+		return value, b[1:], nil
+	}
+	var v int64
+	v, rest, err = TakeInt64Desc(b)
+	if err != nil {
+		return value, b, withNullExpected(err)
+	}
+	return sql.NullInt64{Valid: true, Int64: v}, rest, nil
+}
+
+// TakeInt64Desc takes an int64 value encoded by AppendInt64Desc from b
+// and returns it and the rest of b.
+func TakeInt64Desc(b []byte) (value int64, rest []byte, err error) {
+	var buf [9]byte
+	a := invertPrefix(buf[:], b)
+	value, rest, err = TakeInt64(a)
+	if err != nil {
+		return 0, b, invertDecodeError(b, err)
+	}
+	return value, b[len(a)-len(rest):], nil
+}
+
+// AppendNullUint32Desc appends a NullUint32 value to dst in the descending order.
+//
+// You need to store the result of AppendNullUint32Desc like:
+//     dst = sortedbytes.AppendNullUint32Desc(dst, value)
+func AppendNullUint32Desc(dst []byte, value NullUint32) []byte {
+	if value.Valid {
+		return AppendUint32Desc(dst, value.Uint32)
+	}
+	return append(dst, typeCodeNull)
+}
+
+// AppendUint32Desc appends an uint32 value to dst in the descending order.
+// The result is the bitwise inversion of the result of AppendUint32.
+//
+// You need to store the result of AppendUint32Desc like:
+//     dst = sortedbytes.AppendUint32Desc(dst, value)
+func AppendUint32Desc(dst []byte, value uint32) []byte {
+	start := len(dst)
+	dst = AppendUint32(dst, value)
+	invertBytes(dst[start:])
+	return dst
+}
+
+// TakeNullUint32Desc takes a NullUint32 value encoded by AppendNullUint32Desc from b
+// and returns it and the rest of b.
+func TakeNullUint32Desc(b []byte) (value NullUint32, rest []byte, err error) {
+	if len(b) > 0 && b[0] == typeCodeNull {
+		return value, b[1:], nil
+	}
+	var v uint32
+	v, rest, err = TakeUint32Desc(b)
+	if err != nil {
+		return value, b, withNullExpected(err)
+	}
+	return NullUint32{Valid: true, Uint32: v}, rest, nil
+}
+
+// TakeUint32Desc takes an uint32 value encoded by AppendUint32Desc from b
+// and returns it and the rest of b.
+func TakeUint32Desc(b []byte) (value uint32, rest []byte, err error) {
+	var buf [5]byte
+	a := invertPrefix(buf[:], b)
+	value, rest, err = TakeUint32(a)
+	if err != nil {
+		return 0, b, invertDecodeError(b, err)
+	}
+	return value, b[len(a)-len(rest):], nil
+}
+
+// AppendNullUint64Desc appends a NullUint64 value to dst in the descending order.
+//
+// You need to store the result of AppendNullUint64Desc like:
+//     dst = sortedbytes.AppendNullUint64Desc(dst, value)
+func AppendNullUint64Desc(dst []byte, value NullUint64) []byte {
+	if value.Valid {
+		return AppendUint64Desc(dst, value.Uint64)
+	}
+	return append(dst, typeCodeNull)
+}
+
+// AppendUint64Desc appends an uint64 value to dst in the descending order.
+// The result is the bitwise inversion of the result of AppendUint64.
+//
+// You need to store the result of AppendUint64Desc like:
+//     dst = sortedbytes.AppendUint64Desc(dst, value)
+func AppendUint64Desc(dst []byte, value uint64) []byte {
+	start := len(dst)
+	dst = AppendUint64(dst, value)
+	invertBytes(dst[start:])
+	return dst
+}
+
+// TakeNullUint64Desc takes a NullUint64 value encoded by AppendNullUint64Desc from b
+// and returns it and the rest of b.
+func TakeNullUint64Desc(b []byte) (value NullUint64, rest []byte, err error) {
+	if len(b) > 0 && b[0] == typeCodeNull {
+		return value, b[1:], nil
+	}
+	var v uint64
+	v, rest, err = TakeUint64Desc(b)
+	if err != nil {
+		return value, b, withNullExpected(err)
+	}
+	return NullUint64{Valid: true, Uint64: v}, rest, nil
+}
+
+// TakeUint64Desc takes an uint64 value encoded by AppendUint64Desc from b
+// and returns it and the rest of b.
+func TakeUint64Desc(b []byte) (value uint64, rest []byte, err error) {
+	var buf [9]byte
+	a := invertPrefix(buf[:], b)
+	value, rest, err = TakeUint64(a)
+	if err != nil {
+		return 0, b, invertDecodeError(b, err)
+	}
+	return value, b[len(a)-len(rest):], nil
+}
+
+// AppendNullVarIntDesc appends a NullInt64 value to dst in the descending order.
+//
+// You need to store the result of AppendNullVarIntDesc like:
+//     dst = sortedbytes.AppendNullVarIntDesc(dst, value)
+func AppendNullVarIntDesc(dst []byte, value sql.NullInt64) []byte {
+	if value.Valid {
+		return AppendVarIntDesc(dst, value.Int64)
+	}
+	return append(dst, typeCodeNull)
+}
+
+// AppendVarIntDesc appends an int64 value to dst in the descending order.
+// The result is the bitwise inversion of the result of AppendVarInt.
+//
+// You need to store the result of AppendVarIntDesc like:
+//     dst = sortedbytes.AppendVarIntDesc(dst, value)
+func AppendVarIntDesc(dst []byte, value int64) []byte {
+	start := len(dst)
+	dst = AppendVarInt(dst, value)
+	invertBytes(dst[start:])
+	return dst
+}
+
+// TakeNullVarIntDesc takes a NullInt64 value encoded by AppendNullVarIntDesc from b
+// and returns it and the rest of b.
+func TakeNullVarIntDesc(b []byte) (value sql.NullInt64, rest []byte, err error) {
+	if len(b) > 0 && b[0] == typeCodeNull {
+		return value, b[1:], nil
+	}
+	var v int64
+	v, rest, err = TakeVarIntDesc(b)
+	if err != nil {
+		return value, b, withNullExpected(err)
+	}
+	return sql.NullInt64{Valid: true, Int64: v}, rest, nil
+}
+
+// TakeVarIntDesc takes an int64 value encoded by AppendVarIntDesc from b
+// and returns it and the rest of b.
+func TakeVarIntDesc(b []byte) (value int64, rest []byte, err error) {
+	var buf [9]byte
+	a := invertPrefix(buf[:], b)
+	value, rest, err = TakeVarInt(a)
+	if err != nil {
+		return 0, b, invertDecodeError(b, err)
+	}
+	return value, b[len(a)-len(rest):], nil
+}
+
+// AppendBigIntDesc appends a *big.Int value to dst in the descending order.
+// The result is the bitwise inversion of the result of AppendBigInt.
+//
+// You need to store the result of AppendBigIntDesc like:
+//     dst = sortedbytes.AppendBigIntDesc(dst, value)
+func AppendBigIntDesc(dst []byte, value *big.Int) []byte {
+	start := len(dst)
+	dst = AppendBigInt(dst, value)
+	invertBytes(dst[start:])
+	return dst
+}
+
+// TakeBigIntDesc takes a *big.Int value encoded by AppendBigIntDesc from b
+// and returns it and the rest of b.
+func TakeBigIntDesc(b []byte) (value *big.Int, rest []byte, err error) {
+	var buf [257]byte
+	a := invertPrefix(buf[:], b)
+	value, rest, err = TakeBigInt(a)
+	if err != nil {
+		return nil, b, invertDecodeError(b, err)
+	}
+	return value, b[len(a)-len(rest):], nil
+}
+
+// AppendNullFloat32Desc appends a NullFloat32 value to dst in the descending order.
+//
+// You need to store the result of AppendNullFloat32Desc like:
+//     dst = sortedbytes.AppendNullFloat32Desc(dst, value)
+func AppendNullFloat32Desc(dst []byte, value NullFloat32) []byte {
+	if value.Valid {
+		return AppendFloat32Desc(dst, value.Float32)
+	}
+	return append(dst, typeCodeNull)
+}
+
+// AppendFloat32Desc appends a float32 value to dst in the descending order.
+// The result is the bitwise inversion of the result of AppendFloat32.
+//
+// You need to store the result of AppendFloat32Desc like:
+//     dst = sortedbytes.AppendFloat32Desc(dst, value)
+func AppendFloat32Desc(dst []byte, value float32) []byte {
+	start := len(dst)
+	dst = AppendFloat32(dst, value)
+	invertBytes(dst[start:])
+	return dst
+}
+
+// TakeNullFloat32Desc takes a NullFloat32 value encoded by AppendNullFloat32Desc from b
+// and returns it and the rest of b.
+func TakeNullFloat32Desc(b []byte) (value NullFloat32, rest []byte, err error) {
+	if len(b) > 0 && b[0] == typeCodeNull {
+		return value, b[1:], nil
+	}
+	var v float32
+	v, rest, err = TakeFloat32Desc(b)
+	if err != nil {
+		return value, b, withNullExpected(err)
+	}
+	return NullFloat32{Valid: true, Float32: v}, rest, nil
+}
+
+// TakeFloat32Desc takes a float32 value encoded by AppendFloat32Desc from b
+// and returns it and the rest of b.
+func TakeFloat32Desc(b []byte) (value float32, rest []byte, err error) {
+	var buf [5]byte
+	a := invertPrefix(buf[:], b)
+	value, rest, err = TakeFloat32(a)
+	if err != nil {
+		return 0, b, invertDecodeError(b, err)
+	}
+	return value, b[len(a)-len(rest):], nil
+}
+
+// AppendNullFloat64Desc appends a NullFloat64 value to dst in the descending order.
+//
+// You need to store the result of AppendNullFloat64Desc like:
+//     dst = sortedbytes.AppendNullFloat64Desc(dst, value)
+func AppendNullFloat64Desc(dst []byte, value sql.NullFloat64) []byte {
+	if value.Valid {
+		return AppendFloat64Desc(dst, value.Float64)
+	}
+	return append(dst, typeCodeNull)
+}
+
+// AppendFloat64Desc appends a float64 value to dst in the descending order.
+// The result is the bitwise inversion of the result of AppendFloat64.
+//
+// You need to store the result of AppendFloat64Desc like:
+//     dst = sortedbytes.AppendFloat64Desc(dst, value)
+func AppendFloat64Desc(dst []byte, value float64) []byte {
+	start := len(dst)
+	dst = AppendFloat64(dst, value)
+	invertBytes(dst[start:])
+	return dst
+}
+
+// TakeNullFloat64Desc takes a NullFloat64 value encoded by AppendNullFloat64Desc from b
+// and returns it and the rest of b.
+func TakeNullFloat64Desc(b []byte) (value sql.NullFloat64, rest []byte, err error) {
+	if len(b) > 0 && b[0] == typeCodeNull {
+		return value, b[1:], nil
+	}
+	var v float64
+	v, rest, err = TakeFloat64Desc(b)
+	if err != nil {
+		return value, b, withNullExpected(err)
+	}
+	return sql.NullFloat64{Valid: true, Float64: v}, rest, nil
+}
+
+// TakeFloat64Desc takes a float64 value encoded by AppendFloat64Desc from b
+// and returns it and the rest of b.
+func TakeFloat64Desc(b []byte) (value float64, rest []byte, err error) {
+	var buf [9]byte
+	a := invertPrefix(buf[:], b)
+	value, rest, err = TakeFloat64(a)
+	if err != nil {
+		return 0, b, invertDecodeError(b, err)
+	}
+	return value, b[len(a)-len(rest):], nil
+}
+
+// AppendNullBoolDesc appends a NullBool value to dst in the descending order.
+//
+// You need to store the result of AppendNullBoolDesc like:
+//     dst = sortedbytes.AppendNullBoolDesc(dst, value)
+func AppendNullBoolDesc(dst []byte, value sql.NullBool) []byte {
+	if value.Valid {
+		return AppendBoolDesc(dst, value.Bool)
+	}
+	return append(dst, typeCodeNull)
+}
+
+// AppendBoolDesc appends a bool value to dst in the descending order.
+// The result is the bitwise inversion of the result of AppendBool.
+//
+// You need to store the result of AppendBoolDesc like:
+//     dst = sortedbytes.AppendBoolDesc(dst, value)
+func AppendBoolDesc(dst []byte, value bool) []byte {
+	start := len(dst)
+	dst = AppendBool(dst, value)
+	invertBytes(dst[start:])
+	return dst
+}
+
+// TakeNullBoolDesc takes a NullBool value encoded by AppendNullBoolDesc from b
+// and returns it and the rest of b.
+func TakeNullBoolDesc(b []byte) (value sql.NullBool, rest []byte, err error) {
+	if len(b) > 0 && b[0] == typeCodeNull {
+		return value, b[1:], nil
+	}
+	var v bool
+	v, rest, err = TakeBoolDesc(b)
+	if err != nil {
+		return value, b, withNullExpected(err)
+	}
+	return sql.NullBool{Valid: true, Bool: v}, rest, nil
+}
+
+// TakeBoolDesc takes a bool value encoded by AppendBoolDesc from b
+// and returns it and the rest of b.
+func TakeBoolDesc(b []byte) (value bool, rest []byte, err error) {
+	var buf [1]byte
+	a := invertPrefix(buf[:], b)
+	value, rest, err = TakeBool(a)
+	if err != nil {
+		return false, b, invertDecodeError(b, err)
+	}
+	return value, b[len(a)-len(rest):], nil
+}
+
+// AppendNullTimeDesc appends a NullTime value to dst in the descending order.
+//
+// You need to store the result of AppendNullTimeDesc like:
+//     dst = sortedbytes.AppendNullTimeDesc(dst, value)
+func AppendNullTimeDesc(dst []byte, value sql.NullTime) []byte {
+	if value.Valid {
+		return AppendTimeDesc(dst, value.Time)
+	}
+	return append(dst, typeCodeNull)
+}
+
+// AppendTimeDesc appends a time.Time value to dst in the descending order.
+// The result is the bitwise inversion of the result of AppendTime.
+//
+// You need to store the result of AppendTimeDesc like:
+//     dst = sortedbytes.AppendTimeDesc(dst, value)
+func AppendTimeDesc(dst []byte, value time.Time) []byte {
+	start := len(dst)
+	dst = AppendTime(dst, value)
+	invertBytes(dst[start:])
+	return dst
+}
+
+// TakeNullTimeDesc takes a NullTime value encoded by AppendNullTimeDesc from b
+// and returns it and the rest of b.
+func TakeNullTimeDesc(b []byte) (value sql.NullTime, rest []byte, err error) {
+	if len(b) > 0 && b[0] == typeCodeNull {
+		return value, b[1:], nil
+	}
+	var v time.Time
+	v, rest, err = TakeTimeDesc(b)
+	if err != nil {
+		return value, b, withNullExpected(err)
+	}
+	return sql.NullTime{Valid: true, Time: v}, rest, nil
+}
+
+// TakeTimeDesc takes a time.Time value encoded by AppendTimeDesc from b
+// and returns it and the rest of b.
+func TakeTimeDesc(b []byte) (value time.Time, rest []byte, err error) {
+	var buf [13]byte
+	a := invertPrefix(buf[:], b)
+	value, rest, err = TakeTime(a)
+	if err != nil {
+		return time.Time{}, b, invertDecodeError(b, err)
+	}
+	return value, b[len(a)-len(rest):], nil
+}
+
+// AppendNullUUIDDesc appends a NullUUID value to dst in the descending order.
+//
+// You need to store the result of AppendNullUUIDDesc like:
+//     dst = sortedbytes.AppendNullUUIDDesc(dst, value)
+func AppendNullUUIDDesc(dst []byte, value NullUUID) []byte {
+	if value.Valid {
+		return AppendUUIDDesc(dst, value.UUID)
+	}
+	return append(dst, typeCodeNull)
+}
+
+// AppendUUIDDesc appends an UUID value to dst in the descending order.
+// The result is the bitwise inversion of the result of AppendUUID.
+//
+// You need to store the result of AppendUUIDDesc like:
+//     dst = sortedbytes.AppendUUIDDesc(dst, value)
+func AppendUUIDDesc(dst []byte, value UUID) []byte {
+	start := len(dst)
+	dst = AppendUUID(dst, value)
+	invertBytes(dst[start:])
+	return dst
+}
+
+// TakeNullUUIDDesc takes a NullUUID value encoded by AppendNullUUIDDesc from b
+// and returns it and the rest of b.
+func TakeNullUUIDDesc(b []byte) (value NullUUID, rest []byte, err error) {
+	if len(b) > 0 && b[0] == typeCodeNull {
+		return value, b[1:], nil
+	}
+	var v UUID
+	v, rest, err = TakeUUIDDesc(b)
+	if err != nil {
+		return value, b, withNullExpected(err)
+	}
+	return NullUUID{Valid: true, UUID: v}, rest, nil
+}
+
+// TakeUUIDDesc takes an UUID value encoded by AppendUUIDDesc from b
+// and returns it and the rest of b.
+func TakeUUIDDesc(b []byte) (value UUID, rest []byte, err error) {
+	var buf [17]byte
+	a := invertPrefix(buf[:], b)
+	value, rest, err = TakeUUID(a)
+	if err != nil {
+		return UUID{}, b, invertDecodeError(b, err)
+	}
+	return value, b[len(a)-len(rest):], nil
+}
+
+// invertBytes inverts all bits of b in place.
+func invertBytes(b []byte) {
+	for i := range b {
+		b[i] = ^b[i]
+	}
+}
+
+// invertPrefix copies the inversion of the first len(buf) bytes of b,
+// or all bytes if b is shorter, to buf and returns the copied part of buf.
+func invertPrefix(buf, b []byte) []byte {
+	n := copy(buf, b)
+	invertBytes(buf[:n])
+	return buf[:n]
+}
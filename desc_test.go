@@ -0,0 +1,403 @@
+package sortedbytes_test
+
+import (
+	"bytes"
+	"database/sql"
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hnakamur/sortedbytes"
+)
+
+func TestAppendStringDesc(t *testing.T) {
+	t.Run("order", func(t *testing.T) {
+		testCases := []struct {
+			a, b string
+		}{
+			{a: "\x00", b: ""},
+			{a: "a", b: ""},
+			{a: "a\x00", b: "a"},
+			{a: "a\x01", b: "a\x00"},
+			{a: "a\xff", b: "a"},
+			{a: "bb", b: "bar"},
+		}
+		for i, tc := range testCases {
+			a := sortedbytes.AppendStringDesc([]byte(nil), tc.a)
+			b := sortedbytes.AppendStringDesc([]byte(nil), tc.b)
+			if got, want := bytes.Compare(a, b), -1; got != want {
+				t.Errorf("case %d: compare result unmatch: got=%d, want=%d, a=0x%x, b=0x%x",
+					i, got, want, a, b)
+			}
+		}
+	})
+	t.Run("orderWithNull", func(t *testing.T) {
+		a := sortedbytes.AppendNullStringDesc([]byte(nil), sql.NullString{})
+		b := sortedbytes.AppendNullStringDesc([]byte(nil), sql.NullString{Valid: true, String: "\xff"})
+		if got, want := bytes.Compare(a, b), -1; got != want {
+			t.Errorf("compare result unmatch: got=%d, want=%d, a=0x%x, b=0x%x", got, want, a, b)
+		}
+	})
+}
+
+func TestTakeStringDesc(t *testing.T) {
+	t.Run("roundtrip", func(t *testing.T) {
+		testCases := []string{
+			"",
+			"foo",
+			"\x00foo",
+			"foo\x00",
+			"f\x00\x00oo",
+			"\x00",
+			"\xff",
+			"\x00\xff",
+			"\x00\x00\xff\xff",
+		}
+		for i, input := range testCases {
+			b := sortedbytes.AppendStringDesc([]byte(nil), input)
+			v, rest, err := sortedbytes.TakeStringDesc(b)
+			if err != nil {
+				t.Errorf("case %d: got error: %s", i, err)
+			}
+			if got, want := v, input; got != want {
+				t.Errorf("case %d: value unmatch: got=%q, want=%q", i, got, want)
+			}
+			if got, want := len(rest), 0; got != want {
+				t.Errorf("case %d: rest length unmatch: got=%d, want=%d", i, got, want)
+			}
+		}
+	})
+	t.Run("nullRoundtrip", func(t *testing.T) {
+		testCases := []sql.NullString{
+			{Valid: false, String: ""},
+			{Valid: true, String: ""},
+			{Valid: true, String: "foo\x00"},
+		}
+		for i, input := range testCases {
+			b := sortedbytes.AppendNullStringDesc([]byte(nil), input)
+			v, rest, err := sortedbytes.TakeNullStringDesc(b)
+			if err != nil {
+				t.Errorf("case %d: got error: %s", i, err)
+			}
+			if got, want := v, input; got != want {
+				t.Errorf("case %d: value unmatch: got=%+v, want=%+v", i, got, want)
+			}
+			if got, want := len(rest), 0; got != want {
+				t.Errorf("case %d: rest length unmatch: got=%d, want=%d", i, got, want)
+			}
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		testCases := [][]byte{
+			[]byte("\xfd"),
+			[]byte("\xfd\x99"),
+			[]byte("\xfd\x99\xff"),
+			[]byte("\xfd\x99\xff\x01"),
+			[]byte("\x02\x00"),
+		}
+		for i, input := range testCases {
+			_, _, err := sortedbytes.TakeStringDesc(input)
+			if err == nil {
+				t.Errorf("case %d: got no error", i)
+			}
+		}
+	})
+}
+
+func TestAppendBytesDesc(t *testing.T) {
+	t.Run("order", func(t *testing.T) {
+		testCases := []struct {
+			a, b []byte
+		}{
+			{a: []byte("\x00"), b: []byte("")},
+			{a: []byte("a\x00"), b: []byte("a")},
+			{a: []byte("\xff\x00"), b: []byte("\xff")},
+			{a: []byte("\xff"), b: []byte("\xfe")},
+		}
+		for i, tc := range testCases {
+			a := sortedbytes.AppendBytesDesc([]byte(nil), tc.a)
+			b := sortedbytes.AppendBytesDesc([]byte(nil), tc.b)
+			if got, want := bytes.Compare(a, b), -1; got != want {
+				t.Errorf("case %d: compare result unmatch: got=%d, want=%d, a=0x%x, b=0x%x",
+					i, got, want, a, b)
+			}
+		}
+	})
+}
+
+func TestTakeBytesDesc(t *testing.T) {
+	t.Run("roundtrip", func(t *testing.T) {
+		testCases := []sortedbytes.NullBytes{
+			{Valid: false, Bytes: nil},
+			{Valid: true, Bytes: []byte{}},
+			{Valid: true, Bytes: []byte("\x00\xff\x00")},
+			{Valid: true, Bytes: []byte("\xff\xff")},
+		}
+		for i, input := range testCases {
+			b := sortedbytes.AppendNullBytesDesc([]byte(nil), input)
+			v, rest, err := sortedbytes.TakeNullBytesDesc(b)
+			if err != nil {
+				t.Errorf("case %d: got error: %s", i, err)
+			}
+			if got, want := v, input; !reflect.DeepEqual(got, want) {
+				t.Errorf("case %d: value unmatch: got=%+v, want=%+v", i, got, want)
+			}
+			if got, want := len(rest), 0; got != want {
+				t.Errorf("case %d: rest length unmatch: got=%d, want=%d", i, got, want)
+			}
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		testCases := [][]byte{
+			[]byte("\xfe"),
+			[]byte("\xfe\xff"),
+			[]byte("\xfe\xff\x01"),
+			[]byte("\x01\x00"),
+		}
+		for i, input := range testCases {
+			_, _, err := sortedbytes.TakeBytesDesc(input)
+			if err == nil {
+				t.Errorf("case %d: got no error", i)
+			}
+		}
+	})
+}
+
+func TestAppendFixedDesc(t *testing.T) {
+	testCases := []struct {
+		name   string
+		values []interface{}
+		append func(dst []byte, v interface{}) []byte
+	}{
+		{
+			name:   "int32",
+			values: []interface{}{int32(math.MinInt32), int32(-1), int32(0), int32(1), int32(math.MaxInt32)},
+			append: func(dst []byte, v interface{}) []byte { return sortedbytes.AppendInt32Desc(dst, v.(int32)) },
+		},
+		{
+			name:   "int64",
+			values: []interface{}{int64(math.MinInt64), int64(-1), int64(0), int64(1), int64(math.MaxInt64)},
+			append: func(dst []byte, v interface{}) []byte { return sortedbytes.AppendInt64Desc(dst, v.(int64)) },
+		},
+		{
+			name:   "uint32",
+			values: []interface{}{uint32(0), uint32(1), uint32(math.MaxUint32)},
+			append: func(dst []byte, v interface{}) []byte { return sortedbytes.AppendUint32Desc(dst, v.(uint32)) },
+		},
+		{
+			name:   "uint64",
+			values: []interface{}{uint64(0), uint64(1), uint64(math.MaxUint64)},
+			append: func(dst []byte, v interface{}) []byte { return sortedbytes.AppendUint64Desc(dst, v.(uint64)) },
+		},
+		{
+			name:   "varint",
+			values: []interface{}{int64(math.MinInt64), int64(-0x100), int64(-1), int64(0), int64(1), int64(0x100), int64(math.MaxInt64)},
+			append: func(dst []byte, v interface{}) []byte { return sortedbytes.AppendVarIntDesc(dst, v.(int64)) },
+		},
+		{
+			name:   "bigint",
+			values: []interface{}{mustParseBigInt("-0x10000000000000000"), big.NewInt(-1), big.NewInt(0), mustParseBigInt("0x10000000000000000")},
+			append: func(dst []byte, v interface{}) []byte { return sortedbytes.AppendBigIntDesc(dst, v.(*big.Int)) },
+		},
+		{
+			name:   "float32",
+			values: []interface{}{float32(math.Inf(-1)), float32(-1.5), float32(0), float32(1.5), float32(math.Inf(1))},
+			append: func(dst []byte, v interface{}) []byte { return sortedbytes.AppendFloat32Desc(dst, v.(float32)) },
+		},
+		{
+			name:   "float64",
+			values: []interface{}{math.Inf(-1), -1.5, 0.0, 1.5, math.Inf(1)},
+			append: func(dst []byte, v interface{}) []byte { return sortedbytes.AppendFloat64Desc(dst, v.(float64)) },
+		},
+		{
+			name:   "bool",
+			values: []interface{}{false, true},
+			append: func(dst []byte, v interface{}) []byte { return sortedbytes.AppendBoolDesc(dst, v.(bool)) },
+		},
+		{
+			name:   "time",
+			values: []interface{}{time.Time{}, time.Unix(0, 0), time.Unix(0, 1)},
+			append: func(dst []byte, v interface{}) []byte { return sortedbytes.AppendTimeDesc(dst, v.(time.Time)) },
+		},
+		{
+			name:   "uuid",
+			values: []interface{}{sortedbytes.UUID{}, sortedbytes.UUID{15: 1}, sortedbytes.UUID{0: 1}},
+			append: func(dst []byte, v interface{}) []byte { return sortedbytes.AppendUUIDDesc(dst, v.(sortedbytes.UUID)) },
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for i := 1; i < len(tc.values); i++ {
+				a := tc.append([]byte(nil), tc.values[i-1])
+				b := tc.append([]byte(nil), tc.values[i])
+				if got, want := bytes.Compare(a, b), 1; got != want {
+					t.Errorf("case %d: compare result unmatch: got=%d, want=%d, a=0x%x, b=0x%x",
+						i, got, want, a, b)
+				}
+			}
+		})
+	}
+}
+
+func TestTakeFixedDesc(t *testing.T) {
+	t.Run("roundtrip", func(t *testing.T) {
+		now := time.Date(2020, 5, 17, 12, 34, 56, 789, time.UTC)
+		b := sortedbytes.AppendInt32Desc([]byte(nil), math.MinInt32)
+		b = sortedbytes.AppendNullInt32Desc(b, sql.NullInt32{})
+		b = sortedbytes.AppendInt64Desc(b, math.MaxInt64)
+		b = sortedbytes.AppendNullInt64Desc(b, sql.NullInt64{Valid: true, Int64: -2})
+		b = sortedbytes.AppendUint32Desc(b, math.MaxUint32)
+		b = sortedbytes.AppendNullUint32Desc(b, sortedbytes.NullUint32{Valid: true, Uint32: 3})
+		b = sortedbytes.AppendUint64Desc(b, math.MaxUint64)
+		b = sortedbytes.AppendNullUint64Desc(b, sortedbytes.NullUint64{})
+		b = sortedbytes.AppendVarIntDesc(b, -0x1234)
+		b = sortedbytes.AppendNullVarIntDesc(b, sql.NullInt64{Valid: true, Int64: 7})
+		b = sortedbytes.AppendBigIntDesc(b, mustParseBigInt("-0x10000000000000000"))
+		b = sortedbytes.AppendFloat64Desc(b, -1.5)
+		b = sortedbytes.AppendNullFloat64Desc(b, sql.NullFloat64{Valid: true, Float64: 2.5})
+		b = sortedbytes.AppendBoolDesc(b, true)
+		b = sortedbytes.AppendNullBoolDesc(b, sql.NullBool{Valid: true, Bool: false})
+		b = sortedbytes.AppendTimeDesc(b, now)
+		b = sortedbytes.AppendNullTimeDesc(b, sql.NullTime{})
+		b = sortedbytes.AppendUUIDDesc(b, sortedbytes.UUID{0: 1, 15: 2})
+		b = sortedbytes.AppendNullUUIDDesc(b, sortedbytes.NullUUID{Valid: true, UUID: sortedbytes.UUID{3}})
+		b = sortedbytes.AppendFloat32Desc(b, -0.5)
+		b = sortedbytes.AppendNullFloat32Desc(b, sortedbytes.NullFloat32{Valid: true, Float32: 4})
+
+		var got []interface{}
+		var err error
+		take := func(v interface{}, rest []byte, e error) {
+			if err == nil && e != nil {
+				err = e
+			}
+			got = append(got, v)
+			b = rest
+		}
+		take(sortedbytes.TakeInt32Desc(b))
+		take(sortedbytes.TakeNullInt32Desc(b))
+		take(sortedbytes.TakeInt64Desc(b))
+		take(sortedbytes.TakeNullInt64Desc(b))
+		take(sortedbytes.TakeUint32Desc(b))
+		take(sortedbytes.TakeNullUint32Desc(b))
+		take(sortedbytes.TakeUint64Desc(b))
+		take(sortedbytes.TakeNullUint64Desc(b))
+		take(sortedbytes.TakeVarIntDesc(b))
+		take(sortedbytes.TakeNullVarIntDesc(b))
+		take(sortedbytes.TakeBigIntDesc(b))
+		take(sortedbytes.TakeFloat64Desc(b))
+		take(sortedbytes.TakeNullFloat64Desc(b))
+		take(sortedbytes.TakeBoolDesc(b))
+		take(sortedbytes.TakeNullBoolDesc(b))
+		take(sortedbytes.TakeTimeDesc(b))
+		take(sortedbytes.TakeNullTimeDesc(b))
+		take(sortedbytes.TakeUUIDDesc(b))
+		take(sortedbytes.TakeNullUUIDDesc(b))
+		take(sortedbytes.TakeFloat32Desc(b))
+		take(sortedbytes.TakeNullFloat32Desc(b))
+		if err != nil {
+			t.Fatalf("got error: %s", err)
+		}
+		want := []interface{}{
+			int32(math.MinInt32),
+			sql.NullInt32{},
+			int64(math.MaxInt64),
+			sql.NullInt64{Valid: true, Int64: -2},
+			uint32(math.MaxUint32),
+			sortedbytes.NullUint32{Valid: true, Uint32: 3},
+			uint64(math.MaxUint64),
+			sortedbytes.NullUint64{},
+			int64(-0x1234),
+			sql.NullInt64{Valid: true, Int64: 7},
+			mustParseBigInt("-0x10000000000000000"),
+			-1.5,
+			sql.NullFloat64{Valid: true, Float64: 2.5},
+			true,
+			sql.NullBool{Valid: true, Bool: false},
+			now,
+			sql.NullTime{},
+			sortedbytes.UUID{0: 1, 15: 2},
+			sortedbytes.NullUUID{Valid: true, UUID: sortedbytes.UUID{3}},
+			float32(-0.5),
+			sortedbytes.NullFloat32{Valid: true, Float32: 4},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("values unmatch:\n got=%v\nwant=%v", got, want)
+		}
+		if got, want := len(b), 0; got != want {
+			t.Errorf("rest length unmatch: got=%d, want=%d", got, want)
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		testCases := [][]byte{
+			[]byte("\xe6"),
+			[]byte("\xe6\xff\xff\xff"),
+			[]byte("\x19\x00\x00\x00\x01"),
+		}
+		for i, input := range testCases {
+			v, rest, err := sortedbytes.TakeInt32Desc(input)
+			if err == nil {
+				t.Errorf("case %d: got no error", i)
+			}
+			if v != 0 || !bytes.Equal(rest, input) {
+				t.Errorf("case %d: unexpected result on error: v=%d, rest=0x%x", i, v, rest)
+			}
+		}
+	})
+}
+
+func TestAppendDescCompositeKey(t *testing.T) {
+	type key struct {
+		userID    int64
+		name      sql.NullString
+		createdAt sql.NullTime
+	}
+	appendKey := func(k key) []byte {
+		b := sortedbytes.AppendInt64([]byte(nil), k.userID)
+		b = sortedbytes.AppendNullString(b, k.name)
+		return sortedbytes.AppendNullTimeDesc(b, k.createdAt)
+	}
+	t.Run("order", func(t *testing.T) {
+		testCases := []key{
+			{userID: 1, name: sql.NullString{Valid: true, String: "a"}, createdAt: sql.NullTime{}},
+			{userID: 1, name: sql.NullString{Valid: true, String: "a"}, createdAt: sql.NullTime{Valid: true, Time: time.Unix(2, 0)}},
+			{userID: 1, name: sql.NullString{Valid: true, String: "a"}, createdAt: sql.NullTime{Valid: true, Time: time.Unix(1, 0)}},
+			{userID: 1, name: sql.NullString{Valid: true, String: "a\x00"}, createdAt: sql.NullTime{}},
+			{userID: 2, name: sql.NullString{}, createdAt: sql.NullTime{Valid: true, Time: time.Unix(1, 0)}},
+		}
+		for i := 1; i < len(testCases); i++ {
+			a := appendKey(testCases[i-1])
+			b := appendKey(testCases[i])
+			if got, want := bytes.Compare(a, b), -1; got != want {
+				t.Errorf("case %d: compare result unmatch: got=%d, want=%d, a=0x%x, b=0x%x",
+					i, got, want, a, b)
+			}
+		}
+	})
+	t.Run("roundtrip", func(t *testing.T) {
+		k := key{userID: 1, name: sql.NullString{Valid: true, String: "a"}, createdAt: sql.NullTime{}}
+		b := appendKey(k)
+
+		var k2 key
+		var err error
+		k2.userID, b, err = sortedbytes.TakeInt64(b)
+		if err != nil {
+			t.Fatalf(".userID: got error: %s", err)
+		}
+		k2.name, b, err = sortedbytes.TakeNullString(b)
+		if err != nil {
+			t.Fatalf(".name: got error: %s", err)
+		}
+		k2.createdAt, b, err = sortedbytes.TakeNullTimeDesc(b)
+		if err != nil {
+			t.Fatalf(".createdAt: got error: %s", err)
+		}
+		if got, want := k2, k; got != want {
+			t.Errorf("key unmatch: got=%+v, want=%+v", got, want)
+		}
+		if got, want := len(b), 0; got != want {
+			t.Errorf("rest length unmatch: got=%d, want=%d", got, want)
+		}
+	})
+}
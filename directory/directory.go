@@ -0,0 +1,371 @@
+// Package directory provides a directory layer which maps paths like
+// ("app", "orders", "by_user") to short prefixes, like the directory layer
+// of FDB.
+//
+// Each directory is allocated an integer ID, and its keys start with
+// the content prefix followed by the ID encoded with
+// sortedbytes.AppendVarInt, which is much shorter than the path. The mapping
+// is stored in an ordered key-value store through the KV interface.
+package directory
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hnakamur/sortedbytes"
+)
+
+var (
+	// ErrDirectoryExists is returned by Layer.Create and Layer.Move when
+	// the directory already exists.
+	ErrDirectoryExists = errors.New("directory: directory already exists")
+
+	// ErrDirectoryNotFound is returned when the directory or its parent
+	// does not exist.
+	ErrDirectoryNotFound = errors.New("directory: directory not found")
+
+	// ErrInvalidPath is returned when a path is empty where a directory is
+	// required, or a directory is moved into itself.
+	ErrInvalidPath = errors.New("directory: invalid path")
+)
+
+// KV is an ordered key-value store where the directory layer stores
+// the mapping.
+//
+// The Layer methods do several reads and writes, so KV is usually
+// a transaction of a store to make them atomic.
+type KV interface {
+	// Get returns the value of key and true, or false if key does not exist.
+	Get(key []byte) (value []byte, ok bool, err error)
+
+	// Set sets the value of key.
+	Set(key, value []byte) error
+
+	// Delete deletes key. It is not an error if key does not exist.
+	Delete(key []byte) error
+
+	// DeleteRange deletes all keys in r.
+	DeleteRange(r sortedbytes.KeyRange) error
+
+	// Scan calls fn for each key in r in the ascending order of keys.
+	// If fn returns an error, Scan stops and returns the error.
+	// fn must not modify key and value, or retain them after it returns.
+	Scan(r sortedbytes.KeyRange, fn func(key, value []byte) error) error
+}
+
+// Directory is a directory whose keys are in its Subspace.
+type Directory struct {
+	sortedbytes.Subspace
+
+	path []string
+}
+
+// Path returns the path of d.
+func (d Directory) Path() []string {
+	return append([]string(nil), d.path...)
+}
+
+// Layer is a directory layer.
+//
+// The mapping is stored under the node subspace, and the keys of
+// the directories are under the content subspace. They must not overlap.
+type Layer struct {
+	node    sortedbytes.Subspace
+	content sortedbytes.Subspace
+}
+
+// The ID of the root directory, which has no content.
+const rootID = 0
+
+// The keys in the node subspace.
+const (
+	counterKey = "counter" // ("counter") = next ID
+	childKey   = "child"   // ("child", parentID, name) = ID
+)
+
+// NewLayer returns a directory layer which stores the mapping under node
+// and allocates the prefixes of the directories under content.
+//
+// For example, the node subspace with the prefix 0xFE and the empty content
+// subspace do not overlap, since an encoded integer never starts with 0xFE.
+func NewLayer(node, content sortedbytes.Subspace) *Layer {
+	return &Layer{node: node, content: content}
+}
+
+// Create creates the directory of path and returns it. The parent
+// directories are created if they do not exist.
+//
+// It returns ErrDirectoryExists if the directory already exists.
+func (l *Layer) Create(kv KV, path []string) (Directory, error) {
+	d, ok, err := l.createOrOpen(kv, path)
+	if err != nil {
+		return Directory{}, err
+	}
+	if ok {
+		return Directory{}, ErrDirectoryExists
+	}
+	return d, nil
+}
+
+// Open returns the directory of path.
+//
+// It returns ErrDirectoryNotFound if the directory does not exist.
+func (l *Layer) Open(kv KV, path []string) (Directory, error) {
+	if len(path) == 0 {
+		return Directory{}, ErrInvalidPath
+	}
+	id, ok, err := l.find(kv, path)
+	if err != nil {
+		return Directory{}, err
+	}
+	if !ok {
+		return Directory{}, ErrDirectoryNotFound
+	}
+	return l.directory(path, id), nil
+}
+
+// CreateOrOpen returns the directory of path, and creates it and its
+// parent directories if they do not exist.
+func (l *Layer) CreateOrOpen(kv KV, path []string) (Directory, error) {
+	d, _, err := l.createOrOpen(kv, path)
+	return d, err
+}
+
+// Exists reports whether the directory of path exists.
+func (l *Layer) Exists(kv KV, path []string) (bool, error) {
+	if len(path) == 0 {
+		return false, ErrInvalidPath
+	}
+	_, ok, err := l.find(kv, path)
+	return ok, err
+}
+
+// List returns the names of the subdirectories of the directory of path
+// in the ascending order. An empty path lists the top level directories.
+//
+// It returns ErrDirectoryNotFound if the directory does not exist.
+func (l *Layer) List(kv KV, path []string) ([]string, error) {
+	id, ok, err := l.find(kv, path)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrDirectoryNotFound
+	}
+	var names []string
+	err = l.scanChildren(kv, id, func(name string, _ int64) {
+		names = append(names, name)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// Move moves the directory of oldPath to newPath and returns it.
+// The prefix of the directory and its subdirectories does not change,
+// so their keys are not moved.
+//
+// It returns ErrDirectoryNotFound if the directory of oldPath or the parent
+// directory of newPath does not exist, ErrDirectoryExists if the directory
+// of newPath exists, and ErrInvalidPath if newPath is in oldPath.
+func (l *Layer) Move(kv KV, oldPath, newPath []string) (Directory, error) {
+	if len(oldPath) == 0 || len(newPath) == 0 || hasPrefix(newPath, oldPath) {
+		return Directory{}, ErrInvalidPath
+	}
+	id, ok, err := l.find(kv, oldPath)
+	if err != nil {
+		return Directory{}, err
+	}
+	if !ok {
+		return Directory{}, ErrDirectoryNotFound
+	}
+	if _, ok, err := l.find(kv, newPath); err != nil {
+		return Directory{}, err
+	} else if ok {
+		return Directory{}, ErrDirectoryExists
+	}
+	newParentID, ok, err := l.find(kv, newPath[:len(newPath)-1])
+	if err != nil {
+		return Directory{}, err
+	}
+	if !ok {
+		return Directory{}, ErrDirectoryNotFound
+	}
+	oldParentID, _, err := l.find(kv, oldPath[:len(oldPath)-1])
+	if err != nil {
+		return Directory{}, err
+	}
+
+	if err := kv.Delete(l.childKey(oldParentID, oldPath[len(oldPath)-1])); err != nil {
+		return Directory{}, err
+	}
+	if err := kv.Set(l.childKey(newParentID, newPath[len(newPath)-1]), sortedbytes.AppendVarInt(nil, id)); err != nil {
+		return Directory{}, err
+	}
+	return l.directory(newPath, id), nil
+}
+
+// Remove removes the directory of path and its subdirectories, and deletes
+// all keys in them.
+//
+// It returns ErrDirectoryNotFound if the directory does not exist.
+func (l *Layer) Remove(kv KV, path []string) error {
+	if len(path) == 0 {
+		return ErrInvalidPath
+	}
+	id, ok, err := l.find(kv, path)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrDirectoryNotFound
+	}
+	parentID, _, err := l.find(kv, path[:len(path)-1])
+	if err != nil {
+		return err
+	}
+	if err := l.removeTree(kv, id); err != nil {
+		return err
+	}
+	return kv.Delete(l.childKey(parentID, path[len(path)-1]))
+}
+
+func (l *Layer) removeTree(kv KV, id int64) error {
+	var children []int64
+	err := l.scanChildren(kv, id, func(_ string, childID int64) {
+		children = append(children, childID)
+	})
+	if err != nil {
+		return err
+	}
+	for _, childID := range children {
+		if err := l.removeTree(kv, childID); err != nil {
+			return err
+		}
+	}
+	if err := kv.DeleteRange(l.node.Sub(childKey, id).Range()); err != nil {
+		return err
+	}
+	r, err := sortedbytes.PrefixRange(l.content.Sub(id).Bytes())
+	if err != nil {
+		return err
+	}
+	return kv.DeleteRange(r)
+}
+
+func (l *Layer) createOrOpen(kv KV, path []string) (d Directory, existed bool, err error) {
+	if len(path) == 0 {
+		return Directory{}, false, ErrInvalidPath
+	}
+	id := int64(rootID)
+	existed = true
+	for _, name := range path {
+		childID, ok, err := l.child(kv, id, name)
+		if err != nil {
+			return Directory{}, false, err
+		}
+		if !ok {
+			if childID, err = l.allocate(kv); err != nil {
+				return Directory{}, false, err
+			}
+			if err := kv.Set(l.childKey(id, name), sortedbytes.AppendVarInt(nil, childID)); err != nil {
+				return Directory{}, false, err
+			}
+		}
+		id, existed = childID, ok
+	}
+	return l.directory(path, id), existed, nil
+}
+
+// find returns the ID of the directory of path. The ID of the empty path
+// is the ID of the root directory.
+func (l *Layer) find(kv KV, path []string) (id int64, ok bool, err error) {
+	id = rootID
+	for _, name := range path {
+		if id, ok, err = l.child(kv, id, name); err != nil || !ok {
+			return 0, false, err
+		}
+	}
+	return id, true, nil
+}
+
+func (l *Layer) child(kv KV, parentID int64, name string) (id int64, ok bool, err error) {
+	v, ok, err := kv.Get(l.childKey(parentID, name))
+	if err != nil || !ok {
+		return 0, false, err
+	}
+	if id, err = decodeID(v); err != nil {
+		return 0, false, err
+	}
+	return id, true, nil
+}
+
+func (l *Layer) scanChildren(kv KV, parentID int64, fn func(name string, id int64)) error {
+	sub := l.node.Sub(childKey, parentID)
+	return kv.Scan(sub.Range(), func(key, value []byte) error {
+		name, _, err := sortedbytes.TakeString(key[len(sub.Bytes()):])
+		if err != nil {
+			return fmt.Errorf("directory: invalid child key: %w", err)
+		}
+		id, err := decodeID(value)
+		if err != nil {
+			return err
+		}
+		fn(name, id)
+		return nil
+	})
+}
+
+// allocate returns a new ID and increments the counter.
+func (l *Layer) allocate(kv KV) (int64, error) {
+	key := l.node.Pack(sortedbytes.Tuple{counterKey})
+	v, ok, err := kv.Get(key)
+	if err != nil {
+		return 0, err
+	}
+	id := int64(rootID + 1)
+	if ok {
+		if id, err = decodeID(v); err != nil {
+			return 0, err
+		}
+	}
+	if err := kv.Set(key, sortedbytes.AppendVarInt(nil, id+1)); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (l *Layer) childKey(parentID int64, name string) []byte {
+	return l.node.Pack(sortedbytes.Tuple{childKey, parentID, name})
+}
+
+func (l *Layer) directory(path []string, id int64) Directory {
+	return Directory{
+		Subspace: l.content.Sub(id),
+		path:     append([]string(nil), path...),
+	}
+}
+
+func decodeID(v []byte) (int64, error) {
+	id, rest, err := sortedbytes.TakeVarInt(v)
+	if err == nil && len(rest) > 0 {
+		err = sortedbytes.ErrTrailingBytes
+	}
+	if err != nil {
+		return 0, fmt.Errorf("directory: invalid ID: %w", err)
+	}
+	return id, nil
+}
+
+func hasPrefix(path, prefix []string) bool {
+	if len(path) < len(prefix) {
+		return false
+	}
+	for i, name := range prefix {
+		if path[i] != name {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,192 @@
+package directory_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/hnakamur/sortedbytes"
+	"github.com/hnakamur/sortedbytes/directory"
+)
+
+// fakeKV is a directory.KV backed by a map.
+type fakeKV map[string][]byte
+
+func (kv fakeKV) Get(key []byte) ([]byte, bool, error) {
+	v, ok := kv[string(key)]
+	return v, ok, nil
+}
+
+func (kv fakeKV) Set(key, value []byte) error {
+	kv[string(key)] = append([]byte(nil), value...)
+	return nil
+}
+
+func (kv fakeKV) Delete(key []byte) error {
+	delete(kv, string(key))
+	return nil
+}
+
+func (kv fakeKV) DeleteRange(r sortedbytes.KeyRange) error {
+	for k := range kv {
+		if r.Contains([]byte(k)) {
+			delete(kv, k)
+		}
+	}
+	return nil
+}
+
+func (kv fakeKV) Scan(r sortedbytes.KeyRange, fn func(key, value []byte) error) error {
+	var keys []string
+	for k := range kv {
+		if r.Contains([]byte(k)) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := fn([]byte(k), kv[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newLayer() *directory.Layer {
+	return directory.NewLayer(sortedbytes.NewSubspace([]byte{0xfe}), sortedbytes.NewSubspace(nil))
+}
+
+func TestLayer(t *testing.T) {
+	kv := fakeKV{}
+	l := newLayer()
+
+	byUser, err := l.Create(kv, []string{"app", "orders", "by_user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := byUser.Path(), []string{"app", "orders", "by_user"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("path unmatch: got=%v, want=%v", got, want)
+	}
+	if got, want := len(byUser.Bytes()), 2; got != want {
+		t.Errorf("prefix length unmatch: got=%d, want=%d, prefix=0x%x", got, want, byUser.Bytes())
+	}
+	if _, err := l.Create(kv, []string{"app", "orders", "by_user"}); !errors.Is(err, directory.ErrDirectoryExists) {
+		t.Errorf("error unmatch: got=%v, want=%v", err, directory.ErrDirectoryExists)
+	}
+
+	opened, err := l.Open(kv, []string{"app", "orders", "by_user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(opened.Bytes(), byUser.Bytes()) {
+		t.Errorf("prefix unmatch: got=0x%x, want=0x%x", opened.Bytes(), byUser.Bytes())
+	}
+	orders, err := l.CreateOrOpen(kv, []string{"app", "orders"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(orders.Bytes(), byUser.Bytes()) || orders.Contains(byUser.Bytes()) {
+		t.Errorf("prefixes overlap: 0x%x, 0x%x", orders.Bytes(), byUser.Bytes())
+	}
+	if _, err := l.Open(kv, []string{"app", "users"}); !errors.Is(err, directory.ErrDirectoryNotFound) {
+		t.Errorf("error unmatch: got=%v, want=%v", err, directory.ErrDirectoryNotFound)
+	}
+	if _, err := l.CreateOrOpen(kv, []string{"app", "users"}); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := l.Exists(kv, []string{"app", "users"}); err != nil || !ok {
+		t.Errorf("exists unmatch: got=%v, err=%v, want=true", ok, err)
+	}
+
+	list := func(path ...string) []string {
+		t.Helper()
+		names, err := l.List(kv, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return names
+	}
+	if got, want := list(), []string{"app"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("list unmatch: got=%v, want=%v", got, want)
+	}
+	if got, want := list("app"), []string{"orders", "users"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("list unmatch: got=%v, want=%v", got, want)
+	}
+
+	// Move keeps the prefix, so the keys stay in the directory.
+	key := byUser.Pack(sortedbytes.Tuple{"alice", 1})
+	if err := kv.Set(key, []byte("v")); err != nil {
+		t.Fatal(err)
+	}
+	moved, err := l.Move(kv, []string{"app", "orders", "by_user"}, []string{"app", "users", "orders"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(moved.Bytes(), byUser.Bytes()) {
+		t.Errorf("prefix unmatch: got=0x%x, want=0x%x", moved.Bytes(), byUser.Bytes())
+	}
+	if got, want := list("app", "orders"), []string(nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("list unmatch: got=%v, want=%v", got, want)
+	}
+	if got, want := list("app", "users"), []string{"orders"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("list unmatch: got=%v, want=%v", got, want)
+	}
+	for _, tc := range []struct {
+		oldPath, newPath []string
+		err              error
+	}{
+		{oldPath: []string{"app"}, newPath: []string{"app", "x"}, err: directory.ErrInvalidPath},
+		{oldPath: []string{"app", "orders"}, newPath: []string{"app", "users"}, err: directory.ErrDirectoryExists},
+		{oldPath: []string{"app", "nothing"}, newPath: []string{"app", "x"}, err: directory.ErrDirectoryNotFound},
+		{oldPath: []string{"app", "orders"}, newPath: []string{"nothing", "x"}, err: directory.ErrDirectoryNotFound},
+	} {
+		if _, err := l.Move(kv, tc.oldPath, tc.newPath); !errors.Is(err, tc.err) {
+			t.Errorf("move %v to %v: error unmatch: got=%v, want=%v", tc.oldPath, tc.newPath, err, tc.err)
+		}
+	}
+
+	// Remove deletes the subdirectories and their keys.
+	if err := l.Remove(kv, []string{"app", "users"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := kv[string(key)]; ok {
+		t.Errorf("key in removed directory exists")
+	}
+	if ok, err := l.Exists(kv, []string{"app", "users", "orders"}); err != nil || ok {
+		t.Errorf("exists unmatch: got=%v, err=%v, want=false", ok, err)
+	}
+	if got, want := list("app"), []string{"orders"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("list unmatch: got=%v, want=%v", got, want)
+	}
+	if err := l.Remove(kv, []string{"app", "users"}); !errors.Is(err, directory.ErrDirectoryNotFound) {
+		t.Errorf("error unmatch: got=%v, want=%v", err, directory.ErrDirectoryNotFound)
+	}
+
+	// IDs are not reused after removal.
+	users, err := l.Create(kv, []string{"app", "users"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(users.Bytes(), byUser.Bytes()) {
+		t.Errorf("prefix reused: 0x%x", users.Bytes())
+	}
+}
+
+func TestLayerInvalidPath(t *testing.T) {
+	kv := fakeKV{}
+	l := newLayer()
+	if _, err := l.Create(kv, nil); !errors.Is(err, directory.ErrInvalidPath) {
+		t.Errorf("create: error unmatch: got=%v, want=%v", err, directory.ErrInvalidPath)
+	}
+	if _, err := l.Open(kv, nil); !errors.Is(err, directory.ErrInvalidPath) {
+		t.Errorf("open: error unmatch: got=%v, want=%v", err, directory.ErrInvalidPath)
+	}
+	if err := l.Remove(kv, nil); !errors.Is(err, directory.ErrInvalidPath) {
+		t.Errorf("remove: error unmatch: got=%v, want=%v", err, directory.ErrInvalidPath)
+	}
+	if names, err := l.List(kv, nil); err != nil || names != nil {
+		t.Errorf("list: got=%v, err=%v, want empty", names, err)
+	}
+}
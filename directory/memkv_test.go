@@ -0,0 +1,71 @@
+package directory_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/hnakamur/sortedbytes"
+	"github.com/hnakamur/sortedbytes/directory"
+	"github.com/hnakamur/sortedbytes/memkv"
+)
+
+var _ directory.KV = (*memkv.Txn)(nil)
+
+func TestLayerCommit(t *testing.T) {
+	store := memkv.New()
+	l := newLayer()
+
+	txn := store.Begin()
+	created, err := l.Create(txn, []string{"app", "orders"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := txn.Set(created.Pack(sortedbytes.Tuple{"o1"}), []byte("v")); err != nil {
+		t.Fatal(err)
+	}
+	if err := txn.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Two transactions creating directories concurrently conflict on
+	// the counter, so they never get the same prefix.
+	txn1, txn2 := store.Begin(), store.Begin()
+	if _, err := l.Create(txn1, []string{"app", "users"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.Create(txn2, []string{"app", "items"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := txn1.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if err := txn2.Commit(); !errors.Is(err, memkv.ErrConflict) {
+		t.Errorf("error unmatch: got=%v, want=%v", err, memkv.ErrConflict)
+	}
+
+	txn = store.Begin()
+	defer txn.Discard()
+	opened, err := l.Open(txn, []string{"app", "orders"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(opened.Bytes(), created.Bytes()) {
+		t.Errorf("prefix unmatch: got=0x%x, want=0x%x", opened.Bytes(), created.Bytes())
+	}
+	var keys [][]byte
+	err = txn.Scan(opened.Range(), func(key, _ []byte) error {
+		keys = append(keys, append([]byte(nil), key...))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(keys), 1; got != want {
+		t.Fatalf("key count unmatch: got=%d, want=%d", got, want)
+	}
+	if got, err := opened.Unpack(keys[0]); err != nil || !reflect.DeepEqual(got, sortedbytes.Tuple{"o1"}) {
+		t.Errorf("tuple unmatch: got=%v, err=%v, want=%v", got, err, sortedbytes.Tuple{"o1"})
+	}
+}
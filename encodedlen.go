@@ -0,0 +1,224 @@
+package sortedbytes
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// The EncodedLen functions return the number of bytes which the Append
+// function of the same name writes, so that callers can allocate a buffer
+// of the exact size in advance.
+//
+// The Desc counterparts of the Append functions write the same number of
+// bytes as the ascending ones except for strings and byte strings, which
+// have StringDescEncodedLen and BytesDescEncodedLen. A null value of any
+// Null type is written in NullEncodedLen bytes.
+
+// NullEncodedLen is the number of bytes which a null value is encoded in.
+const NullEncodedLen = 1
+
+// StringEncodedLen returns the number of bytes AppendString writes for value.
+func StringEncodedLen(value string) int {
+	return 2 + len(value) + strings.Count(value, "\x00")
+}
+
+// StringDescEncodedLen returns the number of bytes AppendStringDesc writes
+// for value.
+func StringDescEncodedLen(value string) int {
+	return 3 + len(value) + strings.Count(value, "\x00")
+}
+
+// BytesEncodedLen returns the number of bytes AppendBytes writes for value.
+func BytesEncodedLen(value []byte) int {
+	return 2 + len(value) + countZeros(value)
+}
+
+// BytesDescEncodedLen returns the number of bytes AppendBytesDesc writes
+// for value.
+func BytesDescEncodedLen(value []byte) int {
+	return 3 + len(value) + countZeros(value)
+}
+
+func countZeros(b []byte) int {
+	n := 0
+	for _, c := range b {
+		if c == '\x00' {
+			n++
+		}
+	}
+	return n
+}
+
+// Int32EncodedLen returns the number of bytes AppendInt32 writes for value.
+func Int32EncodedLen(value int32) int {
+	if value == 0 {
+		return 1
+	}
+	return 5
+}
+
+// Uint32EncodedLen returns the number of bytes AppendUint32 writes for value.
+func Uint32EncodedLen(value uint32) int {
+	if value == 0 {
+		return 1
+	}
+	return 5
+}
+
+// Int64EncodedLen returns the number of bytes AppendInt64 writes for value.
+func Int64EncodedLen(value int64) int {
+	if value == 0 {
+		return 1
+	}
+	return 9
+}
+
+// Uint64EncodedLen returns the number of bytes AppendUint64 writes for value.
+func Uint64EncodedLen(value uint64) int {
+	if value == 0 {
+		return 1
+	}
+	return 9
+}
+
+// VarIntEncodedLen returns the number of bytes AppendVarInt writes for value.
+func VarIntEncodedLen(value int64) int {
+	switch {
+	case value == 0:
+		return 1
+	case value > 0:
+		return 1 + varIntPayloadLen(uint64(value))
+	default:
+		return 1 + varIntPayloadLen(uint64(-value))
+	}
+}
+
+// BigIntEncodedLen returns the number of bytes AppendBigInt writes for value.
+func BigIntEncodedLen(value *big.Int) int {
+	n := value.BitLen()
+	switch {
+	case n == 0:
+		return 1
+	case n <= 64:
+		return 9
+	default:
+		return 2 + (n+7)/8
+	}
+}
+
+// Float32EncodedLen returns the number of bytes AppendFloat32 writes for value.
+func Float32EncodedLen(value float32) int {
+	return 5
+}
+
+// Float64EncodedLen returns the number of bytes AppendFloat64 writes for value.
+func Float64EncodedLen(value float64) int {
+	return 9
+}
+
+// BoolEncodedLen returns the number of bytes AppendBool writes for value.
+func BoolEncodedLen(value bool) int {
+	return 1
+}
+
+// TimeEncodedLen returns the number of bytes AppendTime writes for value.
+func TimeEncodedLen(value time.Time) int {
+	return 13
+}
+
+// UUIDEncodedLen returns the number of bytes AppendUUID writes for value.
+func UUIDEncodedLen(value UUID) int {
+	return 17
+}
+
+// VersionstampEncodedLen returns the number of bytes AppendVersionstamp
+// writes for value.
+func VersionstampEncodedLen(value Versionstamp) int {
+	return 13
+}
+
+// NestedEncodedLen returns the number of bytes AppendNested writes for value.
+//
+// NestedEncodedLen panics if value contains an element of an unsupported
+// type.
+func NestedEncodedLen(value Tuple) int {
+	n := 2
+	for i, e := range value {
+		if e == nil {
+			n += 2
+			continue
+		}
+		n += tupleElementEncodedLen(i, e)
+	}
+	return n
+}
+
+// EncodedLen returns the number of bytes Pack returns for t.
+//
+// EncodedLen panics if t contains an element of an unsupported type.
+func (t Tuple) EncodedLen() int {
+	n := 0
+	for i, e := range t {
+		n += tupleElementEncodedLen(i, e)
+	}
+	return n
+}
+
+func tupleElementEncodedLen(i int, e interface{}) int {
+	switch v := e.(type) {
+	case nil:
+		return NullEncodedLen
+	case string:
+		return StringEncodedLen(v)
+	case []byte:
+		return BytesEncodedLen(v)
+	case int:
+		return VarIntEncodedLen(int64(v))
+	case int8:
+		return VarIntEncodedLen(int64(v))
+	case int16:
+		return VarIntEncodedLen(int64(v))
+	case int32:
+		return VarIntEncodedLen(int64(v))
+	case int64:
+		return VarIntEncodedLen(v)
+	case uint:
+		return tupleUintEncodedLen(uint64(v))
+	case uint8:
+		return tupleUintEncodedLen(uint64(v))
+	case uint16:
+		return tupleUintEncodedLen(uint64(v))
+	case uint32:
+		return tupleUintEncodedLen(uint64(v))
+	case uint64:
+		return tupleUintEncodedLen(v)
+	case *big.Int:
+		return BigIntEncodedLen(v)
+	case float32:
+		return Float32EncodedLen(v)
+	case float64:
+		return Float64EncodedLen(v)
+	case bool:
+		return BoolEncodedLen(v)
+	case time.Time:
+		return TimeEncodedLen(v)
+	case UUID:
+		return UUIDEncodedLen(v)
+	case Versionstamp:
+		return VersionstampEncodedLen(v)
+	case Tuple:
+		return NestedEncodedLen(v)
+	default:
+		panic(fmt.Sprintf("sortedbytes: unsupported type %T for tuple element at index %d", e, i))
+	}
+}
+
+func tupleUintEncodedLen(v uint64) int {
+	if v > math.MaxInt64 {
+		return Uint64EncodedLen(v)
+	}
+	return VarIntEncodedLen(int64(v))
+}
@@ -0,0 +1,79 @@
+package sortedbytes_test
+
+import (
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/hnakamur/sortedbytes"
+)
+
+func TestEncodedLen(t *testing.T) {
+	strs := []string{"", "foo", "\x00", "f\x00\x00oo\x00", "\xff\xff"}
+	ints := []int64{0, 1, -1, 0xff, -0xff, 0x100, 0xffffffff, 0x100000000, -0x100000000,
+		0xffffffffff, 0x10000000000, math.MaxInt32, math.MinInt32, math.MaxInt64, math.MinInt64}
+	bigInts := []*big.Int{
+		big.NewInt(0),
+		big.NewInt(-1),
+		new(big.Int).SetUint64(math.MaxUint64),
+		new(big.Int).Neg(new(big.Int).SetUint64(math.MaxUint64)),
+		mustParseBigInt("0x10000000000000000"),
+		mustParseBigInt("-0x10000000000000000"),
+		mustParseBigInt("0x123456789abcdef0123456789abcdef"),
+	}
+
+	type testCase struct {
+		got  int
+		want []byte
+	}
+	var testCases []testCase
+	add := func(got int, want []byte) {
+		testCases = append(testCases, testCase{got: got, want: want})
+	}
+	for _, s := range strs {
+		add(sortedbytes.StringEncodedLen(s), sortedbytes.AppendString(nil, s))
+		add(sortedbytes.StringDescEncodedLen(s), sortedbytes.AppendStringDesc(nil, s))
+		add(sortedbytes.BytesEncodedLen([]byte(s)), sortedbytes.AppendBytes(nil, []byte(s)))
+		add(sortedbytes.BytesDescEncodedLen([]byte(s)), sortedbytes.AppendBytesDesc(nil, []byte(s)))
+	}
+	for _, v := range ints {
+		add(sortedbytes.Int32EncodedLen(int32(v)), sortedbytes.AppendInt32(nil, int32(v)))
+		add(sortedbytes.Uint32EncodedLen(uint32(v)), sortedbytes.AppendUint32(nil, uint32(v)))
+		add(sortedbytes.Int64EncodedLen(v), sortedbytes.AppendInt64(nil, v))
+		add(sortedbytes.Uint64EncodedLen(uint64(v)), sortedbytes.AppendUint64(nil, uint64(v)))
+		add(sortedbytes.VarIntEncodedLen(v), sortedbytes.AppendVarInt(nil, v))
+		add(sortedbytes.VarIntEncodedLen(v), sortedbytes.AppendVarIntDesc(nil, v))
+		add(sortedbytes.BigIntEncodedLen(big.NewInt(v)), sortedbytes.AppendBigInt(nil, big.NewInt(v)))
+	}
+	for _, v := range bigInts {
+		add(sortedbytes.BigIntEncodedLen(v), sortedbytes.AppendBigInt(nil, v))
+		add(sortedbytes.BigIntEncodedLen(v), sortedbytes.AppendBigIntDesc(nil, v))
+	}
+	add(sortedbytes.Float32EncodedLen(1.5), sortedbytes.AppendFloat32(nil, 1.5))
+	add(sortedbytes.Float64EncodedLen(1.5), sortedbytes.AppendFloat64(nil, 1.5))
+	add(sortedbytes.BoolEncodedLen(true), sortedbytes.AppendBool(nil, true))
+	add(sortedbytes.TimeEncodedLen(time.Unix(1, 2)), sortedbytes.AppendTime(nil, time.Unix(1, 2)))
+	add(sortedbytes.UUIDEncodedLen(sortedbytes.UUID{}), sortedbytes.AppendUUID(nil, sortedbytes.UUID{}))
+	add(sortedbytes.VersionstampEncodedLen(sortedbytes.Versionstamp{}),
+		sortedbytes.AppendVersionstamp(nil, sortedbytes.Versionstamp{}))
+
+	tuples := []sortedbytes.Tuple{
+		{},
+		{nil},
+		{"a\x00b", []byte{0}, 1, int8(-2), int16(300), int32(-70000), int64(1 << 40)},
+		{uint(1), uint8(2), uint16(3), uint32(4), uint64(math.MaxUint64), mustParseBigInt("-0x10000000000000000")},
+		{float32(1), 2.0, true, time.Unix(0, 0), sortedbytes.UUID{1}, sortedbytes.IncompleteVersionstamp(1)},
+		{sortedbytes.Tuple{nil, "foo", sortedbytes.Tuple{nil}}, nil},
+	}
+	for _, tup := range tuples {
+		add(tup.EncodedLen(), tup.Pack())
+		add(sortedbytes.NestedEncodedLen(tup), sortedbytes.AppendNested(nil, tup))
+	}
+
+	for i, tc := range testCases {
+		if got, want := tc.got, len(tc.want); got != want {
+			t.Errorf("case %d: length unmatch: got=%d, want=%d, encoded=0x%x", i, got, want, tc.want)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package sortedbytes
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var (
+	// ErrUnexpectedTypeCode is returned when a value starts with a type code
+	// which is not expected by the Take function.
+	ErrUnexpectedTypeCode = errors.New("unexpected type code")
+
+	// ErrValueOutOfRange is returned when a value is encoded correctly but
+	// it does not fit in the type of the Take function.
+	ErrValueOutOfRange = errors.New("value out of range")
+
+	// ErrInvalidEscape is returned when a string or a byte string in
+	// the descending order contains an invalid escape sequence.
+	ErrInvalidEscape = errors.New("invalid escape sequence")
+
+	// ErrNonCanonical is returned by the Take functions for the canonical
+	// form like TakeFloat64Canonical when a value is not in the canonical
+	// form, and by the strict Take functions like TakeInt64Strict and
+	// Validate when a value is not encoded in the form which the Append
+	// functions produce.
+	ErrNonCanonical = errors.New("value not in canonical form")
+
+	// ErrInvalidUTF8 is returned by TakeStringStrict and Validate when
+	// a string is not valid UTF-8.
+	ErrInvalidUTF8 = errors.New("invalid UTF-8 string")
+
+	// ErrInvalidPrefix is returned by Strinc and PrefixRange when a prefix
+	// is empty or consists only of 0xFF bytes.
+	ErrInvalidPrefix = errors.New("prefix is empty or only 0xFF bytes")
+
+	// ErrKeyNotInSubspace is returned by Subspace.Unpack when a key does not
+	// start with the prefix of the subspace.
+	ErrKeyNotInSubspace = errors.New("key not in subspace")
+
+	// ErrNoIncompleteVersionstamp is returned by PackWithVersionstamp when
+	// a tuple contains no incomplete versionstamp.
+	ErrNoIncompleteVersionstamp = errors.New("no incomplete versionstamp in tuple")
+
+	// ErrMultipleIncompleteVersionstamps is returned by PackWithVersionstamp
+	// when a tuple contains more than one incomplete versionstamp.
+	ErrMultipleIncompleteVersionstamps = errors.New("multiple incomplete versionstamps in tuple")
+
+	// ErrTrailingBytes is returned by Unmarshal when bytes are left after
+	// the last field.
+	ErrTrailingBytes = errors.New("trailing bytes after last field")
+)
+
+// DecodeError is the error returned by Take functions, Unpack, and Unmarshal.
+//
+// Err is one of ErrUnexpectedTypeCode, ErrValueOutOfRange, ErrInvalidEscape,
+// ErrNonCanonical, ErrInvalidUTF8, ErrTrailingBytes, or io.ErrUnexpectedEOF
+// when the input is truncated, so you can check it with errors.Is.
+type DecodeError struct {
+	// Offset is the offset in the input of the start of the value which
+	// failed to be decoded, or the length of the input if the input is
+	// truncated.
+	Offset int
+
+	// TypeCode is the type code at the start of the value, or 0 if
+	// the input is empty.
+	TypeCode byte
+
+	// Expected is the type codes which the Take function accepts.
+	// It is nil for Skip, SkipN, Validate, and ErrTrailingBytes.
+	Expected []byte
+
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	switch {
+	case errors.Is(e.Err, ErrUnexpectedTypeCode):
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "sortedbytes: %s 0x%02x at offset %d", e.Err, e.TypeCode, e.Offset)
+		if len(e.Expected) > 0 {
+			sb.WriteString(", expected")
+			for _, c := range e.Expected {
+				fmt.Fprintf(&sb, " 0x%02x", c)
+			}
+		}
+		return sb.String()
+	case errors.Is(e.Err, io.ErrUnexpectedEOF):
+		return fmt.Sprintf("sortedbytes: %s at offset %d", e.Err, e.Offset)
+	default:
+		return fmt.Sprintf("sortedbytes: %s at offset %d, type code 0x%02x", e.Err, e.Offset, e.TypeCode)
+	}
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// AddOffset returns a copy of err with offset added to Offset if err is
+// a *DecodeError. Otherwise it returns err as is.
+//
+// It is useful to report the offset in a composite key when a Take function
+// fails, for example:
+//
+//     v, rest, err := sortedbytes.TakeInt64(rest)
+//     if err != nil {
+//         return sortedbytes.AddOffset(err, len(key)-len(rest))
+//     }
+//
+// Note Take functions return the given bytes as rest on error.
+func AddOffset(err error, offset int) error {
+	e, ok := err.(*DecodeError)
+	if !ok {
+		return err
+	}
+	e2 := *e
+	e2.Offset += offset
+	return &e2
+}
+
+var (
+	expectedString  = []byte{typeCodeUTF8String}
+	expectedBytes   = []byte{typeCodeByteString}
+	expectedInt32   = []byte{typeCodeNegativeInt32, typeCodeIntZero, typeCodePositiveInt32}
+	expectedInt64   = []byte{typeCodeNegativeInt64, typeCodeIntZero, typeCodePositiveInt64}
+	expectedVarInt  = typeCodeRange(typeCodeNegativeInt64, typeCodePositiveInt64)
+	expectedBigInt  = typeCodeRange(typeCodeNegativeBigInt, typeCodePositiveBigInt)
+	expectedFloat32 = []byte{typeCodeFloat32}
+	expectedFloat64 = []byte{typeCodeFloat64}
+	expectedBool    = []byte{typeCodeFalse, typeCodeTrue}
+	expectedTime    = []byte{typeCodeTime}
+	expectedNested  = []byte{typeCodeNested}
+	expectedUUID    = []byte{typeCodeUUID}
+
+	expectedVersionstamp = []byte{typeCodeVersionstamp}
+
+	expectedStringDesc = []byte{^byte(typeCodeUTF8String)}
+	expectedBytesDesc  = []byte{^byte(typeCodeByteString)}
+)
+
+func typeCodeRange(first, last byte) []byte {
+	codes := make([]byte, 0, int(last-first)+1)
+	for c := first; c <= last; c++ {
+		codes = append(codes, c)
+	}
+	return codes
+}
+
+// newDecodeError returns a *DecodeError for err which occurred while
+// decoding a value at the start of b with a Take function accepting
+// the type codes expected. It returns err as is if err is already
+// a *DecodeError.
+func newDecodeError(b []byte, err error, expected []byte) error {
+	if _, ok := err.(*DecodeError); ok {
+		return err
+	}
+	e := &DecodeError{
+		Expected: append([]byte(nil), expected...),
+		Err:      err,
+	}
+	if len(b) > 0 {
+		e.TypeCode = b[0]
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		e.Offset = len(b)
+	}
+	return e
+}
+
+// newNullDecodeError is the same as newDecodeError except that typeCodeNull
+// is also expected.
+func newNullDecodeError(b []byte, err error, expected []byte) error {
+	return newDecodeError(b, err, append([]byte{typeCodeNull}, expected...))
+}
+
+// invertDecodeError converts err returned by a Take function for
+// the inverted bytes of b to the error for the descending order encoding
+// in b.
+func invertDecodeError(b []byte, err error) error {
+	e, ok := err.(*DecodeError)
+	if !ok {
+		return err
+	}
+	e2 := *e
+	e2.TypeCode = 0
+	if len(b) > 0 {
+		e2.TypeCode = b[0]
+	}
+	e2.Expected = make([]byte, len(e.Expected))
+	for i, c := range e.Expected {
+		e2.Expected[i] = ^c
+	}
+	return &e2
+}
+
+// withNullExpected returns a copy of err with typeCodeNull added to Expected
+// if err is a *DecodeError. Otherwise it returns err as is.
+func withNullExpected(err error) error {
+	e, ok := err.(*DecodeError)
+	if !ok {
+		return err
+	}
+	e2 := *e
+	e2.Expected = append([]byte{typeCodeNull}, e.Expected...)
+	return &e2
+}
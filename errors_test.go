@@ -0,0 +1,132 @@
+package sortedbytes_test
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/hnakamur/sortedbytes"
+)
+
+func TestDecodeError(t *testing.T) {
+	t.Run("take", func(t *testing.T) {
+		testCases := []struct {
+			take func([]byte) error
+			b    []byte
+			want sortedbytes.DecodeError
+		}{
+			{
+				take: func(b []byte) error { _, _, err := sortedbytes.TakeInt32(b); return err },
+				b:    []byte("\x02foo\x00"),
+				want: sortedbytes.DecodeError{Offset: 0, TypeCode: 0x02, Expected: []byte{0x0F, 0x14, 0x19}, Err: sortedbytes.ErrUnexpectedTypeCode},
+			},
+			{
+				take: func(b []byte) error { _, _, err := sortedbytes.TakeInt32(b); return err },
+				b:    []byte("\x19\x00\x00"),
+				want: sortedbytes.DecodeError{Offset: 3, TypeCode: 0x19, Expected: []byte{0x0F, 0x14, 0x19}, Err: io.ErrUnexpectedEOF},
+			},
+			{
+				take: func(b []byte) error { _, _, err := sortedbytes.TakeInt32(b); return err },
+				b:    nil,
+				want: sortedbytes.DecodeError{Offset: 0, TypeCode: 0x00, Expected: []byte{0x0F, 0x14, 0x19}, Err: io.ErrUnexpectedEOF},
+			},
+			{
+				take: func(b []byte) error { _, _, err := sortedbytes.TakeUint64(b); return err },
+				b:    sortedbytes.AppendInt64(nil, -1),
+				want: sortedbytes.DecodeError{Offset: 0, TypeCode: 0x0C, Expected: []byte{0x0C, 0x14, 0x1C}, Err: sortedbytes.ErrValueOutOfRange},
+			},
+			{
+				take: func(b []byte) error { _, _, err := sortedbytes.TakeNullString(b); return err },
+				b:    []byte("\x01foo\x00"),
+				want: sortedbytes.DecodeError{Offset: 0, TypeCode: 0x01, Expected: []byte{0x00, 0x02}, Err: sortedbytes.ErrUnexpectedTypeCode},
+			},
+			{
+				take: func(b []byte) error { _, _, err := sortedbytes.TakeInt64Desc(b); return err },
+				b:    sortedbytes.AppendInt32Desc(nil, 1),
+				want: sortedbytes.DecodeError{Offset: 0, TypeCode: 0xE6, Expected: []byte{0xF3, 0xEB, 0xE3}, Err: sortedbytes.ErrUnexpectedTypeCode},
+			},
+			{
+				take: func(b []byte) error { _, _, err := sortedbytes.TakeNullInt64Desc(b); return err },
+				b:    sortedbytes.AppendInt64Desc(nil, 1)[:5],
+				want: sortedbytes.DecodeError{Offset: 5, TypeCode: 0xE3, Expected: []byte{0x00, 0xF3, 0xEB, 0xE3}, Err: io.ErrUnexpectedEOF},
+			},
+			{
+				take: func(b []byte) error { _, _, err := sortedbytes.TakeStringDesc(b); return err },
+				b:    []byte("\xfd\x9e\xff\x01"),
+				want: sortedbytes.DecodeError{Offset: 0, TypeCode: 0xFD, Expected: []byte{0xFD}, Err: sortedbytes.ErrInvalidEscape},
+			},
+		}
+		for i, tc := range testCases {
+			err := tc.take(tc.b)
+			var got *sortedbytes.DecodeError
+			if !errors.As(err, &got) {
+				t.Errorf("case %d: got error %v, want *DecodeError", i, err)
+				continue
+			}
+			if got.Offset != tc.want.Offset || got.TypeCode != tc.want.TypeCode ||
+				!bytes.Equal(got.Expected, tc.want.Expected) || got.Err != tc.want.Err {
+				t.Errorf("case %d: error unmatch: got=%+v, want=%+v", i, *got, tc.want)
+			}
+			if !errors.Is(err, tc.want.Err) {
+				t.Errorf("case %d: errors.Is(err, %v) = false", i, tc.want.Err)
+			}
+		}
+	})
+	t.Run("unpack", func(t *testing.T) {
+		b := append(sortedbytes.Tuple{"foo", 1}.Pack(), 0x03)
+		_, err := sortedbytes.Unpack(b)
+		var got *sortedbytes.DecodeError
+		if !errors.As(err, &got) {
+			t.Fatalf("got error %v, want *DecodeError", err)
+		}
+		if got.Offset != 7 || got.TypeCode != 0x03 || got.Err != sortedbytes.ErrUnexpectedTypeCode {
+			t.Errorf("error unmatch: got=%+v", *got)
+		}
+	})
+	t.Run("unmarshal", func(t *testing.T) {
+		b := sortedbytes.AppendInt32(nil, 1)
+		b = sortedbytes.AppendUint64(b, 2)
+		b = sortedbytes.AppendString(b, "c")
+		var k marshalPositionKey
+		err := sortedbytes.Unmarshal(b, &k)
+		var got *sortedbytes.DecodeError
+		if !errors.As(err, &got) {
+			t.Fatalf("got error %v, want *DecodeError", err)
+		}
+		if got.Offset != 14 || got.TypeCode != 0x02 || got.Err != sortedbytes.ErrUnexpectedTypeCode {
+			t.Errorf("error unmatch: got=%+v", *got)
+		}
+
+		b = sortedbytes.AppendInt32(nil, 1)
+		b = sortedbytes.AppendUint64(b, 2)
+		b = sortedbytes.AppendNullStringDesc(b, sql.NullString{})
+		b = append(b, 0x14)
+		err = sortedbytes.Unmarshal(b, &k)
+		if !errors.As(err, &got) {
+			t.Fatalf("got error %v, want *DecodeError", err)
+		}
+		if got.Offset != 15 || got.TypeCode != 0x14 || got.Err != sortedbytes.ErrTrailingBytes {
+			t.Errorf("error unmatch: got=%+v", *got)
+		}
+	})
+	t.Run("addOffset", func(t *testing.T) {
+		_, _, err := sortedbytes.TakeBool([]byte{0x14})
+		err = sortedbytes.AddOffset(err, 3)
+		var got *sortedbytes.DecodeError
+		if !errors.As(err, &got) {
+			t.Fatalf("got error %v, want *DecodeError", err)
+		}
+		if got.Offset != 3 {
+			t.Errorf("offset unmatch: got=%d, want=3", got.Offset)
+		}
+		if want := "sortedbytes: unexpected type code 0x14 at offset 3, expected 0x26 0x27"; err.Error() != want {
+			t.Errorf("message unmatch: got=%q, want=%q", err.Error(), want)
+		}
+
+		if err := sortedbytes.AddOffset(io.EOF, 3); err != io.EOF {
+			t.Errorf("got %v, want io.EOF", err)
+		}
+	})
+}
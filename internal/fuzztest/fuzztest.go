@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"database/sql"
 	"reflect"
+	"time"
 
 	"github.com/hnakamur/sortedbytes"
 )
@@ -177,3 +178,435 @@ func FuzzTakeNullBool(data []byte) int {
 	}
 	return 1
 }
+
+func FuzzTakeUint32(data []byte) int {
+	v, rest, err := sortedbytes.TakeUint32(data)
+	if err != nil {
+		if v != 0 {
+			panic("v != 0 on error")
+		}
+		if !bytes.Equal(rest, data) {
+			panic("!bytes.Equal(rest, data) on error")
+		}
+		return 0
+	}
+	if len(rest) >= len(data) {
+		panic("len(rest) >= len(data) on success")
+	}
+	return 1
+}
+
+func FuzzTakeNullUint32(data []byte) int {
+	v, rest, err := sortedbytes.TakeNullUint32(data)
+	if err != nil {
+		if !reflect.DeepEqual(v, sortedbytes.NullUint32{Valid: false, Uint32: 0}) {
+			panic("v != sortedbytes.NullUint32{Valid: false, Uint32: 0} on error")
+		}
+		if !bytes.Equal(rest, data) {
+			panic("!bytes.Equal(rest, data) on error")
+		}
+		return 0
+	}
+	if len(rest) >= len(data) {
+		panic("len(rest) >= len(data) on success")
+	}
+	return 1
+}
+
+func FuzzTakeUint64(data []byte) int {
+	v, rest, err := sortedbytes.TakeUint64(data)
+	if err != nil {
+		if v != 0 {
+			panic("v != 0 on error")
+		}
+		if !bytes.Equal(rest, data) {
+			panic("!bytes.Equal(rest, data) on error")
+		}
+		return 0
+	}
+	if len(rest) >= len(data) {
+		panic("len(rest) >= len(data) on success")
+	}
+	return 1
+}
+
+func FuzzTakeNullUint64(data []byte) int {
+	v, rest, err := sortedbytes.TakeNullUint64(data)
+	if err != nil {
+		if !reflect.DeepEqual(v, sortedbytes.NullUint64{Valid: false, Uint64: 0}) {
+			panic("v != sortedbytes.NullUint64{Valid: false, Uint64: 0} on error")
+		}
+		if !bytes.Equal(rest, data) {
+			panic("!bytes.Equal(rest, data) on error")
+		}
+		return 0
+	}
+	if len(rest) >= len(data) {
+		panic("len(rest) >= len(data) on success")
+	}
+	return 1
+}
+
+func FuzzTakeBigInt(data []byte) int {
+	v, rest, err := sortedbytes.TakeBigInt(data)
+	if err != nil {
+		if v != nil {
+			panic("v != nil on error")
+		}
+		if !bytes.Equal(rest, data) {
+			panic("!bytes.Equal(rest, data) on error")
+		}
+		return 0
+	}
+	if len(rest) >= len(data) {
+		panic("len(rest) >= len(data) on success")
+	}
+	return 1
+}
+
+func FuzzTakeVarInt(data []byte) int {
+	v, rest, err := sortedbytes.TakeVarInt(data)
+	if err != nil {
+		if v != 0 {
+			panic("v != 0 on error")
+		}
+		if !bytes.Equal(rest, data) {
+			panic("!bytes.Equal(rest, data) on error")
+		}
+		return 0
+	}
+	if len(rest) >= len(data) {
+		panic("len(rest) >= len(data) on success")
+	}
+	return 1
+}
+
+func FuzzTakeNullVarInt(data []byte) int {
+	v, rest, err := sortedbytes.TakeNullVarInt(data)
+	if err != nil {
+		if !reflect.DeepEqual(v, sql.NullInt64{Valid: false, Int64: 0}) {
+			panic("v != sql.NullInt64{Valid: false, Int64: 0} on error")
+		}
+		if !bytes.Equal(rest, data) {
+			panic("!bytes.Equal(rest, data) on error")
+		}
+		return 0
+	}
+	if len(rest) >= len(data) {
+		panic("len(rest) >= len(data) on success")
+	}
+	return 1
+}
+
+func FuzzTakeTime(data []byte) int {
+	v, rest, err := sortedbytes.TakeTime(data)
+	if err != nil {
+		if !v.IsZero() {
+			panic("!v.IsZero() on error")
+		}
+		if !bytes.Equal(rest, data) {
+			panic("!bytes.Equal(rest, data) on error")
+		}
+		return 0
+	}
+	if len(rest) >= len(data) {
+		panic("len(rest) >= len(data) on success")
+	}
+	return 1
+}
+
+func FuzzTakeNullTime(data []byte) int {
+	v, rest, err := sortedbytes.TakeNullTime(data)
+	if err != nil {
+		if !reflect.DeepEqual(v, sql.NullTime{Valid: false, Time: time.Time{}}) {
+			panic("v != sql.NullTime{Valid: false, Time: time.Time{}} on error")
+		}
+		if !bytes.Equal(rest, data) {
+			panic("!bytes.Equal(rest, data) on error")
+		}
+		return 0
+	}
+	if len(rest) >= len(data) {
+		panic("len(rest) >= len(data) on success")
+	}
+	return 1
+}
+
+func FuzzTakeBytes(data []byte) int {
+	v, rest, err := sortedbytes.TakeBytes(data)
+	if err != nil {
+		if v != nil {
+			panic("v != nil on error")
+		}
+		if !bytes.Equal(rest, data) {
+			panic("!bytes.Equal(rest, data) on error")
+		}
+		return 0
+	}
+	if len(rest) >= len(data) {
+		panic("len(rest) >= len(data) on success")
+	}
+	return 1
+}
+
+func FuzzTakeNullBytes(data []byte) int {
+	v, rest, err := sortedbytes.TakeNullBytes(data)
+	if err != nil {
+		if !reflect.DeepEqual(v, sortedbytes.NullBytes{Valid: false, Bytes: nil}) {
+			panic("v != sortedbytes.NullBytes{Valid: false, Bytes: nil} on error")
+		}
+		if !bytes.Equal(rest, data) {
+			panic("!bytes.Equal(rest, data) on error")
+		}
+		return 0
+	}
+	if len(rest) >= len(data) {
+		panic("len(rest) >= len(data) on success")
+	}
+	return 1
+}
+
+func FuzzTakeStringDesc(data []byte) int {
+	v, rest, err := sortedbytes.TakeStringDesc(data)
+	if err != nil {
+		if v != "" {
+			panic("v != \"\" on error")
+		}
+		if !bytes.Equal(rest, data) {
+			panic("!bytes.Equal(rest, data) on error")
+		}
+		return 0
+	}
+	if len(rest) >= len(data) {
+		panic("len(rest) >= len(data) on success")
+	}
+	return 1
+}
+
+func FuzzTakeBytesDesc(data []byte) int {
+	v, rest, err := sortedbytes.TakeBytesDesc(data)
+	if err != nil {
+		if v != nil {
+			panic("v != nil on error")
+		}
+		if !bytes.Equal(rest, data) {
+			panic("!bytes.Equal(rest, data) on error")
+		}
+		return 0
+	}
+	if len(rest) >= len(data) {
+		panic("len(rest) >= len(data) on success")
+	}
+	return 1
+}
+
+func FuzzTakeInt64Desc(data []byte) int {
+	v, rest, err := sortedbytes.TakeInt64Desc(data)
+	if err != nil {
+		if v != 0 {
+			panic("v != 0 on error")
+		}
+		if !bytes.Equal(rest, data) {
+			panic("!bytes.Equal(rest, data) on error")
+		}
+		return 0
+	}
+	if len(rest) >= len(data) {
+		panic("len(rest) >= len(data) on success")
+	}
+	return 1
+}
+
+func FuzzUnpack(data []byte) int {
+	t, err := sortedbytes.Unpack(data)
+	if err != nil {
+		if t != nil {
+			panic("t != nil on error")
+		}
+		return 0
+	}
+	if !bytes.Equal(t.Pack(), data) {
+		return 0
+	}
+	return 1
+}
+
+func FuzzTakeNested(data []byte) int {
+	v, rest, err := sortedbytes.TakeNested(data)
+	if err != nil {
+		if v != nil {
+			panic("v != nil on error")
+		}
+		if !bytes.Equal(rest, data) {
+			panic("!bytes.Equal(rest, data) on error")
+		}
+		return 0
+	}
+	if len(rest) >= len(data) {
+		panic("len(rest) >= len(data) on success")
+	}
+	return 1
+}
+
+func FuzzTakeUUID(data []byte) int {
+	v, rest, err := sortedbytes.TakeUUID(data)
+	if err != nil {
+		if v != (sortedbytes.UUID{}) {
+			panic("v != (sortedbytes.UUID{}) on error")
+		}
+		if !bytes.Equal(rest, data) {
+			panic("!bytes.Equal(rest, data) on error")
+		}
+		return 0
+	}
+	if len(rest) >= len(data) {
+		panic("len(rest) >= len(data) on success")
+	}
+	return 1
+}
+
+func FuzzTakeNullUUID(data []byte) int {
+	v, rest, err := sortedbytes.TakeNullUUID(data)
+	if err != nil {
+		if v != (sortedbytes.NullUUID{}) {
+			panic("v != (sortedbytes.NullUUID{}) on error")
+		}
+		if !bytes.Equal(rest, data) {
+			panic("!bytes.Equal(rest, data) on error")
+		}
+		return 0
+	}
+	if len(rest) >= len(data) {
+		panic("len(rest) >= len(data) on success")
+	}
+	return 1
+}
+
+func FuzzTakeFloat32(data []byte) int {
+	v, rest, err := sortedbytes.TakeFloat32(data)
+	if err != nil {
+		if v != 0 {
+			panic("v != 0 on error")
+		}
+		if !bytes.Equal(rest, data) {
+			panic("!bytes.Equal(rest, data) on error")
+		}
+		return 0
+	}
+	if len(rest) >= len(data) {
+		panic("len(rest) >= len(data) on success")
+	}
+	return 1
+}
+
+func FuzzTakeNullFloat32(data []byte) int {
+	v, rest, err := sortedbytes.TakeNullFloat32(data)
+	if err != nil {
+		if v != (sortedbytes.NullFloat32{}) {
+			panic("v != (sortedbytes.NullFloat32{}) on error")
+		}
+		if !bytes.Equal(rest, data) {
+			panic("!bytes.Equal(rest, data) on error")
+		}
+		return 0
+	}
+	if len(rest) >= len(data) {
+		panic("len(rest) >= len(data) on success")
+	}
+	return 1
+}
+
+func FuzzTakeVersionstamp(data []byte) int {
+	v, rest, err := sortedbytes.TakeVersionstamp(data)
+	if err != nil {
+		if v != (sortedbytes.Versionstamp{}) {
+			panic("v != (sortedbytes.Versionstamp{}) on error")
+		}
+		if !bytes.Equal(rest, data) {
+			panic("!bytes.Equal(rest, data) on error")
+		}
+		return 0
+	}
+	if len(rest) >= len(data) {
+		panic("len(rest) >= len(data) on success")
+	}
+	return 1
+}
+
+func FuzzTakeFloat64Canonical(data []byte) int {
+	v, rest, err := sortedbytes.TakeFloat64Canonical(data)
+	if err != nil {
+		if v != 0 {
+			panic("v != 0 on error")
+		}
+		if !bytes.Equal(rest, data) {
+			panic("!bytes.Equal(rest, data) on error")
+		}
+		return 0
+	}
+	if len(rest) >= len(data) {
+		panic("len(rest) >= len(data) on success")
+	}
+	return 1
+}
+
+func FuzzTakeFloat32Canonical(data []byte) int {
+	v, rest, err := sortedbytes.TakeFloat32Canonical(data)
+	if err != nil {
+		if v != 0 {
+			panic("v != 0 on error")
+		}
+		if !bytes.Equal(rest, data) {
+			panic("!bytes.Equal(rest, data) on error")
+		}
+		return 0
+	}
+	if len(rest) >= len(data) {
+		panic("len(rest) >= len(data) on success")
+	}
+	return 1
+}
+
+func FuzzSkip(data []byte) int {
+	rest, err := sortedbytes.Skip(data)
+	if err != nil {
+		if !bytes.Equal(rest, data) {
+			panic("!bytes.Equal(rest, data) on error")
+		}
+		return 0
+	}
+	if len(rest) >= len(data) {
+		panic("len(rest) >= len(data) on success")
+	}
+	return 1
+}
+
+func FuzzTakeStringBytes(data []byte) int {
+	v, rest, err := sortedbytes.TakeStringBytes(data, nil)
+	if err != nil {
+		if v != nil {
+			panic("v != nil on error")
+		}
+		if !bytes.Equal(rest, data) {
+			panic("!bytes.Equal(rest, data) on error")
+		}
+		return 0
+	}
+	if len(rest) >= len(data) {
+		panic("len(rest) >= len(data) on success")
+	}
+	return 1
+}
+
+func FuzzValidate(data []byte) int {
+	if err := sortedbytes.Validate(data); err != nil {
+		return 0
+	}
+	for rest := data; len(rest) > 0; {
+		var err error
+		if rest, err = sortedbytes.Skip(rest); err != nil {
+			panic("Skip failed on valid input")
+		}
+	}
+	return 1
+}
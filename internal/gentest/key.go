@@ -0,0 +1,51 @@
+// Package gentest contains a struct type whose codec is generated by
+// sortedbytes-gen for testing the generated code.
+package gentest
+
+import (
+	"database/sql"
+	"math/big"
+	"time"
+
+	"github.com/hnakamur/sortedbytes"
+)
+
+//go:generate go run ../../cmd/sortedbytes-gen -type=Key
+//go:generate go run ../../cmd/sortedbytes-gen -type=SmallKey
+
+// Key is an example composite key.
+type Key struct {
+	TenantID  string                 `sortedbytes:"1"`
+	UserID    int                    `sortedbytes:"2"`
+	CreatedAt time.Time              `sortedbytes:"3,desc"`
+	Deleted   bool                   `sortedbytes:"4"`
+	Score     float64                `sortedbytes:"5"`
+	Count     uint32                 `sortedbytes:"6"`
+	Size      uint                   `sortedbytes:"7"`
+	Version   int32                  `sortedbytes:"8"`
+	Seq       int64                  `sortedbytes:"9,desc"`
+	Total     uint64                 `sortedbytes:"10"`
+	Payload   []byte                 `sortedbytes:"11"`
+	Amount    *big.Int               `sortedbytes:"12"`
+	Name      sql.NullString         `sortedbytes:"13"`
+	Age       sql.NullInt32          `sortedbytes:"14"`
+	Parent    sortedbytes.NullUint64 `sortedbytes:"15"`
+	ID        sortedbytes.UUID       `sortedbytes:"16"`
+	Ratio     float32                `sortedbytes:"17,desc"`
+	Note      string                 `sortedbytes:"-"`
+}
+
+// UserID is a declared type whose underlying type is int64.
+type UserID int64
+
+// Level is a declared type whose underlying type is uint8.
+type Level uint8
+
+// SmallKey is an example composite key with integer types narrower than
+// the encoded ones and declared types.
+type SmallKey struct {
+	Shard int8
+	Flags uint16
+	Owner UserID
+	Level Level `sortedbytes:",desc"`
+}
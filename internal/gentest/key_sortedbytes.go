@@ -0,0 +1,100 @@
+// Code generated by sortedbytes-gen; DO NOT EDIT.
+
+package gentest
+
+import "github.com/hnakamur/sortedbytes"
+
+// AppendKey appends the encoded key of k to dst.
+func (k *Key) AppendKey(dst []byte) []byte {
+	dst = sortedbytes.AppendString(dst, k.TenantID)
+	dst = sortedbytes.AppendInt64(dst, int64(k.UserID))
+	dst = sortedbytes.AppendTimeDesc(dst, k.CreatedAt)
+	dst = sortedbytes.AppendBool(dst, k.Deleted)
+	dst = sortedbytes.AppendFloat64(dst, k.Score)
+	dst = sortedbytes.AppendUint32(dst, k.Count)
+	dst = sortedbytes.AppendUint64(dst, uint64(k.Size))
+	dst = sortedbytes.AppendInt32(dst, k.Version)
+	dst = sortedbytes.AppendInt64Desc(dst, k.Seq)
+	dst = sortedbytes.AppendUint64(dst, k.Total)
+	dst = sortedbytes.AppendBytes(dst, k.Payload)
+	dst = sortedbytes.AppendBigInt(dst, k.Amount)
+	dst = sortedbytes.AppendNullString(dst, k.Name)
+	dst = sortedbytes.AppendNullInt32(dst, k.Age)
+	dst = sortedbytes.AppendNullUint64(dst, k.Parent)
+	dst = sortedbytes.AppendUUID(dst, k.ID)
+	dst = sortedbytes.AppendFloat32Desc(dst, k.Ratio)
+	return dst
+}
+
+// TakeKey takes the encoded key from b into k and returns the rest of b.
+func (k *Key) TakeKey(b []byte) (rest []byte, err error) {
+	rest = b
+	if k.TenantID, rest, err = sortedbytes.TakeString(rest); err != nil {
+		return b, sortedbytes.AddOffset(err, len(b)-len(rest))
+	}
+	{
+		off := len(b) - len(rest)
+		var v int64
+		if v, rest, err = sortedbytes.TakeInt64(rest); err != nil {
+			return b, sortedbytes.AddOffset(err, len(b)-len(rest))
+		}
+		if int64(int(v)) != v {
+			return b, &sortedbytes.DecodeError{Offset: off, TypeCode: b[off], Err: sortedbytes.ErrValueOutOfRange}
+		}
+		k.UserID = int(v)
+	}
+	if k.CreatedAt, rest, err = sortedbytes.TakeTimeDesc(rest); err != nil {
+		return b, sortedbytes.AddOffset(err, len(b)-len(rest))
+	}
+	if k.Deleted, rest, err = sortedbytes.TakeBool(rest); err != nil {
+		return b, sortedbytes.AddOffset(err, len(b)-len(rest))
+	}
+	if k.Score, rest, err = sortedbytes.TakeFloat64(rest); err != nil {
+		return b, sortedbytes.AddOffset(err, len(b)-len(rest))
+	}
+	if k.Count, rest, err = sortedbytes.TakeUint32(rest); err != nil {
+		return b, sortedbytes.AddOffset(err, len(b)-len(rest))
+	}
+	{
+		off := len(b) - len(rest)
+		var v uint64
+		if v, rest, err = sortedbytes.TakeUint64(rest); err != nil {
+			return b, sortedbytes.AddOffset(err, len(b)-len(rest))
+		}
+		if uint64(uint(v)) != v {
+			return b, &sortedbytes.DecodeError{Offset: off, TypeCode: b[off], Err: sortedbytes.ErrValueOutOfRange}
+		}
+		k.Size = uint(v)
+	}
+	if k.Version, rest, err = sortedbytes.TakeInt32(rest); err != nil {
+		return b, sortedbytes.AddOffset(err, len(b)-len(rest))
+	}
+	if k.Seq, rest, err = sortedbytes.TakeInt64Desc(rest); err != nil {
+		return b, sortedbytes.AddOffset(err, len(b)-len(rest))
+	}
+	if k.Total, rest, err = sortedbytes.TakeUint64(rest); err != nil {
+		return b, sortedbytes.AddOffset(err, len(b)-len(rest))
+	}
+	if k.Payload, rest, err = sortedbytes.TakeBytes(rest); err != nil {
+		return b, sortedbytes.AddOffset(err, len(b)-len(rest))
+	}
+	if k.Amount, rest, err = sortedbytes.TakeBigInt(rest); err != nil {
+		return b, sortedbytes.AddOffset(err, len(b)-len(rest))
+	}
+	if k.Name, rest, err = sortedbytes.TakeNullString(rest); err != nil {
+		return b, sortedbytes.AddOffset(err, len(b)-len(rest))
+	}
+	if k.Age, rest, err = sortedbytes.TakeNullInt32(rest); err != nil {
+		return b, sortedbytes.AddOffset(err, len(b)-len(rest))
+	}
+	if k.Parent, rest, err = sortedbytes.TakeNullUint64(rest); err != nil {
+		return b, sortedbytes.AddOffset(err, len(b)-len(rest))
+	}
+	if k.ID, rest, err = sortedbytes.TakeUUID(rest); err != nil {
+		return b, sortedbytes.AddOffset(err, len(b)-len(rest))
+	}
+	if k.Ratio, rest, err = sortedbytes.TakeFloat32Desc(rest); err != nil {
+		return b, sortedbytes.AddOffset(err, len(b)-len(rest))
+	}
+	return rest, nil
+}
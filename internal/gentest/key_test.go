@@ -0,0 +1,136 @@
+package gentest
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hnakamur/sortedbytes"
+)
+
+func TestKey(t *testing.T) {
+	testCases := []Key{
+		{
+			Payload: []byte{},
+			Amount:  big.NewInt(0),
+		},
+		{
+			TenantID:  "foo\x00",
+			UserID:    -1,
+			CreatedAt: time.Date(2020, 5, 17, 12, 34, 56, 789, time.UTC),
+			Deleted:   true,
+			Score:     1.5,
+			Count:     2,
+			Size:      3,
+			Version:   -4,
+			Seq:       5,
+			Total:     6,
+			Payload:   []byte("\x00bar"),
+			Amount:    big.NewInt(-7),
+			Name:      sql.NullString{Valid: true, String: "8"},
+			Age:       sql.NullInt32{Valid: true, Int32: 9},
+			Parent:    sortedbytes.NullUint64{Valid: true, Uint64: 10},
+			ID:        sortedbytes.UUID{0: 0xff, 15: 0x11},
+			Ratio:     0.25,
+		},
+	}
+	for i, input := range testCases {
+		b := input.AppendKey(nil)
+		want, err := sortedbytes.Marshal(input)
+		if err != nil {
+			t.Fatalf("case %d: marshal: got error: %s", i, err)
+		}
+		if !bytes.Equal(b, want) {
+			t.Errorf("case %d: encoded bytes unmatch: got=0x%x, want=0x%x", i, b, want)
+		}
+
+		var got Key
+		rest, err := got.TakeKey(b)
+		if err != nil {
+			t.Fatalf("case %d: got error: %s", i, err)
+		}
+		if len(rest) != 0 {
+			t.Errorf("case %d: rest unmatch: got=0x%x, want=empty", i, rest)
+		}
+		if !reflect.DeepEqual(got, input) {
+			t.Errorf("case %d: value unmatch:\n got=%+v\nwant=%+v", i, got, input)
+		}
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		b := testCases[1].AppendKey(nil)
+		for i := 0; i < len(b)-1; i++ {
+			var k Key
+			_, err := k.TakeKey(b[:i])
+			var de *sortedbytes.DecodeError
+			if !errors.As(err, &de) {
+				t.Errorf("length %d: got error %v, want *DecodeError", i, err)
+				continue
+			}
+			if de.Offset != i {
+				t.Errorf("length %d: offset unmatch: got=%d, want=%d", i, de.Offset, i)
+			}
+		}
+	})
+}
+
+func TestSmallKey(t *testing.T) {
+	testCases := []SmallKey{
+		{},
+		{Shard: -128, Flags: 65535, Owner: -1, Level: 255},
+		{Shard: 127, Flags: 1, Owner: 1 << 40, Level: 1},
+	}
+	for i, input := range testCases {
+		b := input.AppendKey(nil)
+		want, err := sortedbytes.Marshal(input)
+		if err != nil {
+			t.Fatalf("case %d: marshal: got error: %s", i, err)
+		}
+		if !bytes.Equal(b, want) {
+			t.Errorf("case %d: encoded bytes unmatch: got=0x%x, want=0x%x", i, b, want)
+		}
+
+		var got SmallKey
+		rest, err := got.TakeKey(b)
+		if err != nil {
+			t.Fatalf("case %d: got error: %s", i, err)
+		}
+		if len(rest) != 0 {
+			t.Errorf("case %d: rest unmatch: got=0x%x, want=empty", i, rest)
+		}
+		if got != input {
+			t.Errorf("case %d: value unmatch: got=%+v, want=%+v", i, got, input)
+		}
+	}
+
+	t.Run("outOfRange", func(t *testing.T) {
+		testCases := []struct {
+			b      []byte
+			offset int
+		}{
+			{b: sortedbytes.AppendInt32(nil, 128), offset: 0},
+			{b: sortedbytes.AppendUint32(sortedbytes.AppendInt32(nil, 0), 65536), offset: 1},
+			{b: sortedbytes.AppendUint32Desc(sortedbytes.AppendInt64(sortedbytes.AppendUint32(sortedbytes.AppendInt32(nil, 0), 0), 0), 256), offset: 3},
+		}
+		for i, tc := range testCases {
+			var k SmallKey
+			_, err := k.TakeKey(tc.b)
+			if !errors.Is(err, sortedbytes.ErrValueOutOfRange) {
+				t.Errorf("case %d: got error %v, want ErrValueOutOfRange", i, err)
+				continue
+			}
+			var de *sortedbytes.DecodeError
+			if !errors.As(err, &de) {
+				t.Errorf("case %d: got error %v, want *DecodeError", i, err)
+				continue
+			}
+			if de.Offset != tc.offset {
+				t.Errorf("case %d: offset unmatch: got=%d, want=%d", i, de.Offset, tc.offset)
+			}
+		}
+	})
+}
@@ -0,0 +1,60 @@
+// Code generated by sortedbytes-gen; DO NOT EDIT.
+
+package gentest
+
+import "github.com/hnakamur/sortedbytes"
+
+// AppendKey appends the encoded key of s to dst.
+func (s *SmallKey) AppendKey(dst []byte) []byte {
+	dst = sortedbytes.AppendInt32(dst, int32(s.Shard))
+	dst = sortedbytes.AppendUint32(dst, uint32(s.Flags))
+	dst = sortedbytes.AppendInt64(dst, int64(s.Owner))
+	dst = sortedbytes.AppendUint32Desc(dst, uint32(s.Level))
+	return dst
+}
+
+// TakeKey takes the encoded key from b into s and returns the rest of b.
+func (s *SmallKey) TakeKey(b []byte) (rest []byte, err error) {
+	rest = b
+	{
+		off := len(b) - len(rest)
+		var v int32
+		if v, rest, err = sortedbytes.TakeInt32(rest); err != nil {
+			return b, sortedbytes.AddOffset(err, len(b)-len(rest))
+		}
+		if int32(int8(v)) != v {
+			return b, &sortedbytes.DecodeError{Offset: off, TypeCode: b[off], Err: sortedbytes.ErrValueOutOfRange}
+		}
+		s.Shard = int8(v)
+	}
+	{
+		off := len(b) - len(rest)
+		var v uint32
+		if v, rest, err = sortedbytes.TakeUint32(rest); err != nil {
+			return b, sortedbytes.AddOffset(err, len(b)-len(rest))
+		}
+		if uint32(uint16(v)) != v {
+			return b, &sortedbytes.DecodeError{Offset: off, TypeCode: b[off], Err: sortedbytes.ErrValueOutOfRange}
+		}
+		s.Flags = uint16(v)
+	}
+	{
+		var v int64
+		if v, rest, err = sortedbytes.TakeInt64(rest); err != nil {
+			return b, sortedbytes.AddOffset(err, len(b)-len(rest))
+		}
+		s.Owner = UserID(v)
+	}
+	{
+		off := len(b) - len(rest)
+		var v uint32
+		if v, rest, err = sortedbytes.TakeUint32Desc(rest); err != nil {
+			return b, sortedbytes.AddOffset(err, len(b)-len(rest))
+		}
+		if uint32(Level(v)) != v {
+			return b, &sortedbytes.DecodeError{Offset: off, TypeCode: b[off], Err: sortedbytes.ErrValueOutOfRange}
+		}
+		s.Level = Level(v)
+	}
+	return rest, nil
+}
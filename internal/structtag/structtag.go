@@ -0,0 +1,51 @@
+// Package structtag parses the "sortedbytes" struct field tags which are
+// shared by sortedbytes.Marshal and the sortedbytes-gen command.
+package structtag
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Name is the key of the struct field tag.
+const Name = "sortedbytes"
+
+// Tag is a parsed struct field tag.
+type Tag struct {
+	// Skip is true if the field must be ignored.
+	Skip bool
+	// Position is the position of the field in the key.
+	// It is valid only if HasPosition is true.
+	Position    int
+	HasPosition bool
+	// Desc is true if the field is encoded in the descending order.
+	Desc bool
+}
+
+// Parse parses the value of the "sortedbytes" struct field tag.
+func Parse(tag string) (Tag, error) {
+	if tag == "-" {
+		return Tag{Skip: true}, nil
+	}
+
+	var t Tag
+	parts := strings.Split(tag, ",")
+	if parts[0] != "" {
+		position, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return Tag{}, fmt.Errorf("invalid position %q in tag", parts[0])
+		}
+		t.Position = position
+		t.HasPosition = true
+	}
+	for _, opt := range parts[1:] {
+		switch opt {
+		case "desc":
+			t.Desc = true
+		default:
+			return Tag{}, fmt.Errorf("unknown option %q in tag", opt)
+		}
+	}
+	return t, nil
+}
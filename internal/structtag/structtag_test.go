@@ -0,0 +1,44 @@
+package structtag_test
+
+import (
+	"testing"
+
+	"github.com/hnakamur/sortedbytes/internal/structtag"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		testCases := []struct {
+			input string
+			want  structtag.Tag
+		}{
+			{input: "", want: structtag.Tag{}},
+			{input: "-", want: structtag.Tag{Skip: true}},
+			{input: ",desc", want: structtag.Tag{Desc: true}},
+			{input: "2", want: structtag.Tag{Position: 2, HasPosition: true}},
+			{input: "0,desc", want: structtag.Tag{Position: 0, HasPosition: true, Desc: true}},
+		}
+		for i, tc := range testCases {
+			got, err := structtag.Parse(tc.input)
+			if err != nil {
+				t.Errorf("case %d: got error: %s", i, err)
+			}
+			if got != tc.want {
+				t.Errorf("case %d: tag unmatch: got=%+v, want=%+v", i, got, tc.want)
+			}
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		testCases := []string{
+			"x",
+			",asc",
+			"1,desc,",
+		}
+		for i, input := range testCases {
+			_, err := structtag.Parse(input)
+			if err == nil {
+				t.Errorf("case %d: got no error", i)
+			}
+		}
+	})
+}
@@ -0,0 +1,130 @@
+package sortedbytes
+
+import "bytes"
+
+// KeyRange is a range of keys from Begin inclusive to End exclusive.
+type KeyRange struct {
+	Begin []byte
+	End   []byte
+}
+
+// Contains reports whether key is in r.
+func (r KeyRange) Contains(key []byte) bool {
+	return bytes.Compare(r.Begin, key) <= 0 && bytes.Compare(key, r.End) < 0
+}
+
+// Strinc returns the first key which is greater than all keys which start
+// with prefix.
+//
+// It strips the trailing 0xFF bytes from prefix and increments the last
+// byte, like strinc in the FDB client libraries. It returns
+// ErrInvalidPrefix if prefix is empty or consists only of 0xFF bytes, since
+// there is no such key.
+func Strinc(prefix []byte) ([]byte, error) {
+	i := len(prefix) - 1
+	for i >= 0 && prefix[i] == '\xFF' {
+		i--
+	}
+	if i < 0 {
+		return nil, ErrInvalidPrefix
+	}
+	end := make([]byte, i+1)
+	copy(end, prefix)
+	end[i]++
+	return end, nil
+}
+
+// PrefixRange returns the range of all keys which start with prefix.
+//
+// The range of a prefix built with Append functions, for example the first
+// components of a composite key, contains the keys whose first components
+// are equal to them. Note that if the last component is a string or a byte
+// string, the range also contains the keys whose component is the string
+// followed by 0x00, since 0x00 is escaped as 0x00 0xFF. Use ComponentRange
+// with the inclusive bounds of the last component to exclude them.
+//
+// It returns ErrInvalidPrefix if prefix is empty or consists only of
+// 0xFF bytes.
+func PrefixRange(prefix []byte) (KeyRange, error) {
+	end, err := Strinc(prefix)
+	if err != nil {
+		return KeyRange{}, err
+	}
+	begin := make([]byte, len(prefix))
+	copy(begin, prefix)
+	return KeyRange{Begin: begin, End: end}, nil
+}
+
+// Bound is a lower or upper bound of a key component for ComponentRange.
+// The zero value is Unbounded.
+type Bound struct {
+	kind  boundKind
+	value []byte
+}
+
+type boundKind int
+
+const (
+	boundUnbounded boundKind = iota
+	boundInclusive
+	boundExclusive
+)
+
+// InclusiveBound returns a bound which includes the component encoded in
+// value by an Append function.
+func InclusiveBound(value []byte) Bound {
+	return Bound{kind: boundInclusive, value: value}
+}
+
+// ExclusiveBound returns a bound which excludes the component encoded in
+// value by an Append function.
+func ExclusiveBound(value []byte) Bound {
+	return Bound{kind: boundExclusive, value: value}
+}
+
+// Unbounded returns a bound which includes all the components.
+func Unbounded() Bound {
+	return Bound{}
+}
+
+// ComponentRange returns the range of the keys which start with prefix
+// followed by a component between lower and upper, and optionally more
+// components after it.
+//
+// For example, the keys of a tenant created between a and b inclusive are
+// in the range:
+//     sortedbytes.ComponentRange(sortedbytes.AppendString(nil, tenant),
+//         sortedbytes.InclusiveBound(sortedbytes.AppendTime(nil, a)),
+//         sortedbytes.InclusiveBound(sortedbytes.AppendTime(nil, b)))
+//
+// The bounds are compared with the encoded bytes, so the lower bound of
+// a component encoded by a Desc function is the greater value. An unbounded
+// range includes a null component, which sorts first, but not the key
+// which is equal to prefix.
+func ComponentRange(prefix []byte, lower, upper Bound) KeyRange {
+	var r KeyRange
+	switch lower.kind {
+	case boundInclusive:
+		r.Begin = concatKey(prefix, lower.value)
+	case boundExclusive:
+		r.Begin = append(concatKey(prefix, lower.value), '\xFF')
+	default:
+		r.Begin = append(concatKey(prefix, nil), '\x00')
+	}
+	switch upper.kind {
+	case boundInclusive:
+		r.End = append(concatKey(prefix, upper.value), '\xFF')
+	case boundExclusive:
+		r.End = concatKey(prefix, upper.value)
+	default:
+		r.End = append(concatKey(prefix, nil), '\xFF')
+	}
+	return r
+}
+
+// concatKey returns a new slice of prefix followed by value with the room
+// for one more byte.
+func concatKey(prefix, value []byte) []byte {
+	key := make([]byte, 0, len(prefix)+len(value)+1)
+	return append(append(key, prefix...), value...)
+}
@@ -0,0 +1,174 @@
+package sortedbytes_test
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/hnakamur/sortedbytes"
+)
+
+func TestStrinc(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		testCases := []struct {
+			input, want []byte
+		}{
+			{input: []byte("\x00"), want: []byte("\x01")},
+			{input: []byte("a"), want: []byte("b")},
+			{input: []byte("a\xff"), want: []byte("b")},
+			{input: []byte("a\xfe\xff\xff"), want: []byte("a\xff")},
+			{input: []byte("\x02foo\x00"), want: []byte("\x02foo\x01")},
+		}
+		for i, tc := range testCases {
+			input := append([]byte(nil), tc.input...)
+			got, err := sortedbytes.Strinc(input)
+			if err != nil {
+				t.Errorf("case %d: got error: %s", i, err)
+				continue
+			}
+			if !bytes.Equal(got, tc.want) {
+				t.Errorf("case %d: result unmatch: got=0x%x, want=0x%x", i, got, tc.want)
+			}
+			if !bytes.Equal(input, tc.input) {
+				t.Errorf("case %d: input modified: got=0x%x, want=0x%x", i, input, tc.input)
+			}
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		testCases := [][]byte{nil, {}, []byte("\xff"), []byte("\xff\xff")}
+		for i, input := range testCases {
+			if _, err := sortedbytes.Strinc(input); !errors.Is(err, sortedbytes.ErrInvalidPrefix) {
+				t.Errorf("case %d: error unmatch: got=%v, want=%v", i, err, sortedbytes.ErrInvalidPrefix)
+			}
+		}
+	})
+}
+
+func TestPrefixRange(t *testing.T) {
+	prefix := sortedbytes.AppendInt64(sortedbytes.AppendString(nil, "tenant"), 1)
+	r, err := sortedbytes.PrefixRange(prefix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prefix[0] = 0
+	if got, want := r.Begin, sortedbytes.AppendInt64(sortedbytes.AppendString(nil, "tenant"), 1); !bytes.Equal(got, want) {
+		t.Errorf("begin unmatch: got=0x%x, want=0x%x", got, want)
+	}
+
+	testCases := []struct {
+		key  []byte
+		want bool
+	}{
+		{key: sortedbytes.AppendInt64(sortedbytes.AppendString(nil, "tenant"), 1), want: true},
+		{key: sortedbytes.AppendString(sortedbytes.AppendInt64(sortedbytes.AppendString(nil, "tenant"), 1), ""), want: true},
+		{key: sortedbytes.AppendUint64(sortedbytes.AppendInt64(sortedbytes.AppendString(nil, "tenant"), 1), 1<<63), want: true},
+		{key: sortedbytes.AppendInt64(sortedbytes.AppendString(nil, "tenant"), 0), want: false},
+		{key: sortedbytes.AppendInt64(sortedbytes.AppendString(nil, "tenant"), 2), want: false},
+		{key: sortedbytes.AppendInt64(sortedbytes.AppendString(nil, "tenant\x00"), 1), want: false},
+		{key: sortedbytes.AppendString(nil, "tenant"), want: false},
+	}
+	for i, tc := range testCases {
+		if got := r.Contains(tc.key); got != tc.want {
+			t.Errorf("case %d: contains unmatch: got=%v, want=%v, key=0x%x", i, got, tc.want, tc.key)
+		}
+	}
+
+	if _, err := sortedbytes.PrefixRange(nil); !errors.Is(err, sortedbytes.ErrInvalidPrefix) {
+		t.Errorf("error unmatch: got=%v, want=%v", err, sortedbytes.ErrInvalidPrefix)
+	}
+}
+
+func TestComponentRange(t *testing.T) {
+	tenant := sortedbytes.AppendString(nil, "tenant")
+	key := func(tenant string, created sql.NullInt64, id string) []byte {
+		b := sortedbytes.AppendString(nil, tenant)
+		b = sortedbytes.AppendNullInt64(b, created)
+		return sortedbytes.AppendString(b, id)
+	}
+	valid := func(v int64) sql.NullInt64 { return sql.NullInt64{Int64: v, Valid: true} }
+	created := func(v int64) []byte { return sortedbytes.AppendInt64(nil, v) }
+
+	keys := [][]byte{
+		tenant,
+		key("tenant", sql.NullInt64{}, "a"),
+		key("tenant", valid(-1), "a"),
+		key("tenant", valid(0), ""),
+		key("tenant", valid(1), "a"),
+		key("tenant", valid(1), "b"),
+		key("tenant", valid(2), "a"),
+		key("tenant", valid(3), "a"),
+		key("tenant\x00", valid(1), "a"),
+		key("tenant2", valid(1), "a"),
+	}
+	testCases := []struct {
+		lower, upper sortedbytes.Bound
+		want         []int
+	}{
+		{lower: sortedbytes.Unbounded(), upper: sortedbytes.Unbounded(), want: []int{1, 2, 3, 4, 5, 6, 7}},
+		{lower: sortedbytes.InclusiveBound(created(1)), upper: sortedbytes.InclusiveBound(created(2)), want: []int{4, 5, 6}},
+		{lower: sortedbytes.ExclusiveBound(created(1)), upper: sortedbytes.ExclusiveBound(created(3)), want: []int{6}},
+		{lower: sortedbytes.ExclusiveBound(created(0)), upper: sortedbytes.Unbounded(), want: []int{4, 5, 6, 7}},
+		{lower: sortedbytes.Unbounded(), upper: sortedbytes.ExclusiveBound(created(0)), want: []int{1, 2}},
+		{lower: sortedbytes.InclusiveBound(created(-1)), upper: sortedbytes.InclusiveBound(created(-1)), want: []int{2}},
+		{lower: sortedbytes.InclusiveBound([]byte{0}), upper: sortedbytes.InclusiveBound([]byte{0}), want: []int{1}},
+		{lower: sortedbytes.ExclusiveBound([]byte{0}), upper: sortedbytes.InclusiveBound(created(0)), want: []int{2, 3}},
+		{lower: sortedbytes.InclusiveBound(created(2)), upper: sortedbytes.InclusiveBound(created(1)), want: nil},
+	}
+	for i, tc := range testCases {
+		r := sortedbytes.ComponentRange(tenant, tc.lower, tc.upper)
+		var got []int
+		for j, k := range keys {
+			if r.Contains(k) {
+				got = append(got, j)
+			}
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("case %d: keys unmatch: got=%v, want=%v", i, got, tc.want)
+		}
+	}
+
+	t.Run("string", func(t *testing.T) {
+		prefix := sortedbytes.AppendString(nil, "tenant")
+		prefix = prefix[:len(prefix):len(prefix)]
+		v := sortedbytes.AppendString(nil, "a")
+		r := sortedbytes.ComponentRange(prefix, sortedbytes.InclusiveBound(v), sortedbytes.InclusiveBound(v))
+		testCases := []struct {
+			key  []byte
+			want bool
+		}{
+			{key: sortedbytes.AppendString(append(prefix, v...), "x"), want: true},
+			{key: sortedbytes.AppendString(prefix, "a\x00"), want: false},
+			{key: sortedbytes.AppendString(prefix, "a\x00b"), want: false},
+			{key: sortedbytes.AppendString(prefix, ""), want: false},
+		}
+		for i, tc := range testCases {
+			if got := r.Contains(tc.key); got != tc.want {
+				t.Errorf("case %d: contains unmatch: got=%v, want=%v, key=0x%x", i, got, tc.want, tc.key)
+			}
+		}
+	})
+	t.Run("desc", func(t *testing.T) {
+		prefix := sortedbytes.AppendString(nil, "tenant")
+		prefix = prefix[:len(prefix):len(prefix)]
+		r := sortedbytes.ComponentRange(prefix,
+			sortedbytes.InclusiveBound(sortedbytes.AppendStringDesc(nil, "b")),
+			sortedbytes.ExclusiveBound(sortedbytes.AppendStringDesc(nil, "a")))
+		testCases := []struct {
+			key  []byte
+			want bool
+		}{
+			{key: sortedbytes.AppendStringDesc(prefix, "c"), want: false},
+			{key: sortedbytes.AppendStringDesc(prefix, "b"), want: true},
+			{key: sortedbytes.AppendInt64(sortedbytes.AppendStringDesc(prefix, "b"), 1), want: true},
+			{key: sortedbytes.AppendStringDesc(prefix, "a\x00"), want: true},
+			{key: sortedbytes.AppendStringDesc(prefix, "a"), want: false},
+		}
+		for i, tc := range testCases {
+			if got := r.Contains(tc.key); got != tc.want {
+				t.Errorf("case %d: contains unmatch: got=%v, want=%v, key=0x%x", i, got, tc.want, tc.key)
+			}
+		}
+	})
+}
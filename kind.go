@@ -0,0 +1,152 @@
+package sortedbytes
+
+import "io"
+
+// Kind is the kind of an encoded value, which tells which Take function
+// can decode the value.
+//
+// Integers have three kinds by their type codes. KindInt32 is the fixed
+// size form of AppendInt32 and AppendUint32, and KindInt64 is the fixed size
+// form of AppendInt64 and AppendUint64, which AppendVarInt also produces
+// for values which need 8 bytes. KindInt is the other forms of
+// AppendVarInt, including zero, which TakeInt32, TakeInt64, and their
+// unsigned counterparts cannot decode unless the value is zero.
+// TakeVarInt and TakeBigInt can decode the integers of all three kinds.
+type Kind int
+
+// Kinds of encoded values.
+const (
+	KindInvalid      Kind = iota
+	KindNull              // null value of any Null type
+	KindBytes             // TakeBytes
+	KindString            // TakeString
+	KindNested            // TakeNested
+	KindInt               // TakeVarInt
+	KindInt32             // TakeInt32, TakeUint32, or TakeVarInt
+	KindInt64             // TakeInt64, TakeUint64, or TakeVarInt
+	KindBigInt            // TakeBigInt
+	KindFloat32           // TakeFloat32
+	KindFloat64           // TakeFloat64
+	KindBool              // TakeBool
+	KindUUID              // TakeUUID
+	KindVersionstamp      // TakeVersionstamp
+	KindTime              // TakeTime
+	KindBytesDesc         // TakeBytesDesc
+	KindStringDesc        // TakeStringDesc
+	KindIntDesc           // TakeVarIntDesc
+	KindInt32Desc         // TakeInt32Desc, TakeUint32Desc, or TakeVarIntDesc
+	KindInt64Desc         // TakeInt64Desc, TakeUint64Desc, or TakeVarIntDesc
+	KindBigIntDesc        // TakeBigIntDesc
+	KindFloat32Desc       // TakeFloat32Desc
+	KindFloat64Desc       // TakeFloat64Desc
+	KindBoolDesc          // TakeBoolDesc
+	KindUUIDDesc          // TakeUUIDDesc
+	KindTimeDesc          // TakeTimeDesc
+)
+
+var kindNames = [...]string{
+	KindInvalid:      "invalid",
+	KindNull:         "null",
+	KindBytes:        "bytes",
+	KindString:       "string",
+	KindNested:       "nested",
+	KindInt:          "int",
+	KindInt32:        "int32",
+	KindInt64:        "int64",
+	KindBigInt:       "bigint",
+	KindFloat32:      "float32",
+	KindFloat64:      "float64",
+	KindBool:         "bool",
+	KindUUID:         "uuid",
+	KindVersionstamp: "versionstamp",
+	KindTime:         "time",
+	KindBytesDesc:    "bytes desc",
+	KindStringDesc:   "string desc",
+	KindIntDesc:      "int desc",
+	KindInt32Desc:    "int32 desc",
+	KindInt64Desc:    "int64 desc",
+	KindBigIntDesc:   "bigint desc",
+	KindFloat32Desc:  "float32 desc",
+	KindFloat64Desc:  "float64 desc",
+	KindBoolDesc:     "bool desc",
+	KindUUIDDesc:     "uuid desc",
+	KindTimeDesc:     "time desc",
+}
+
+func (k Kind) String() string {
+	if k < 0 || int(k) >= len(kindNames) {
+		return "invalid"
+	}
+	return kindNames[k]
+}
+
+// Desc reports whether k is a kind of values in the descending order.
+func (k Kind) Desc() bool {
+	return k >= KindBytesDesc && k <= KindTimeDesc
+}
+
+// PeekKind returns the kind of the encoded value at the start of b
+// without consuming it.
+//
+// It only looks at the type code, so it does not report an error if
+// the value is truncated or malformed after the type code.
+func PeekKind(b []byte) (Kind, error) {
+	if len(b) == 0 {
+		return KindInvalid, newDecodeError(b, io.ErrUnexpectedEOF, nil)
+	}
+	c := b[0]
+	switch {
+	case c == typeCodeNull:
+		return KindNull, nil
+	case c == typeCodeByteString:
+		return KindBytes, nil
+	case c == typeCodeUTF8String:
+		return KindString, nil
+	case c == typeCodeNested:
+		return KindNested, nil
+	case c == typeCodeNegativeBigInt || c == typeCodePositiveBigInt:
+		return KindBigInt, nil
+	case c == typeCodeNegativeInt32 || c == typeCodePositiveInt32:
+		return KindInt32, nil
+	case c == typeCodeNegativeInt64 || c == typeCodePositiveInt64:
+		return KindInt64, nil
+	case typeCodeNegativeInt64 < c && c < typeCodePositiveInt64:
+		return KindInt, nil
+	case c == typeCodeFloat32:
+		return KindFloat32, nil
+	case c == typeCodeFloat64:
+		return KindFloat64, nil
+	case c == typeCodeFalse || c == typeCodeTrue:
+		return KindBool, nil
+	case c == typeCodeUUID:
+		return KindUUID, nil
+	case c == typeCodeVersionstamp:
+		return KindVersionstamp, nil
+	case c == typeCodeTime:
+		return KindTime, nil
+	case c == ^byte(typeCodeByteString):
+		return KindBytesDesc, nil
+	case c == ^byte(typeCodeUTF8String):
+		return KindStringDesc, nil
+	case c == ^byte(typeCodeNegativeBigInt) || c == ^byte(typeCodePositiveBigInt):
+		return KindBigIntDesc, nil
+	case c == ^byte(typeCodeNegativeInt32) || c == ^byte(typeCodePositiveInt32):
+		return KindInt32Desc, nil
+	case c == ^byte(typeCodeNegativeInt64) || c == ^byte(typeCodePositiveInt64):
+		return KindInt64Desc, nil
+	case ^byte(typeCodePositiveInt64) < c && c < ^byte(typeCodeNegativeInt64):
+		return KindIntDesc, nil
+	case c == ^byte(typeCodeFloat32):
+		return KindFloat32Desc, nil
+	case c == ^byte(typeCodeFloat64):
+		return KindFloat64Desc, nil
+	case c == ^byte(typeCodeFalse) || c == ^byte(typeCodeTrue):
+		return KindBoolDesc, nil
+	case c == ^byte(typeCodeUUID):
+		return KindUUIDDesc, nil
+	case c == ^byte(typeCodeTime):
+		return KindTimeDesc, nil
+	default:
+		return KindInvalid, newDecodeError(b, ErrUnexpectedTypeCode, nil)
+	}
+}
@@ -0,0 +1,140 @@
+package sortedbytes_test
+
+import (
+	"database/sql"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/hnakamur/sortedbytes"
+)
+
+func TestPeekKind(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		testCases := []struct {
+			b    []byte
+			want sortedbytes.Kind
+		}{
+			{b: sortedbytes.AppendNullInt64(nil, sql.NullInt64{}), want: sortedbytes.KindNull},
+			{b: sortedbytes.AppendNullInt64Desc(nil, sql.NullInt64{}), want: sortedbytes.KindNull},
+			{b: sortedbytes.AppendBytes(nil, nil), want: sortedbytes.KindBytes},
+			{b: sortedbytes.AppendString(nil, ""), want: sortedbytes.KindString},
+			{b: sortedbytes.AppendNested(nil, sortedbytes.Tuple{}), want: sortedbytes.KindNested},
+			{b: sortedbytes.AppendInt32(nil, -1), want: sortedbytes.KindInt32},
+			{b: sortedbytes.AppendInt32(nil, 1), want: sortedbytes.KindInt32},
+			{b: sortedbytes.AppendUint32(nil, 1), want: sortedbytes.KindInt32},
+			{b: sortedbytes.AppendInt64(nil, -1), want: sortedbytes.KindInt64},
+			{b: sortedbytes.AppendUint64(nil, 1), want: sortedbytes.KindInt64},
+			{b: sortedbytes.AppendVarInt(nil, -1<<62), want: sortedbytes.KindInt64},
+			{b: sortedbytes.AppendInt64(nil, 0), want: sortedbytes.KindInt},
+			{b: sortedbytes.AppendVarInt(nil, 0x100), want: sortedbytes.KindInt},
+			{b: sortedbytes.AppendVarInt(nil, -0x100000000), want: sortedbytes.KindInt},
+			{b: sortedbytes.AppendBigInt(nil, mustParseBigInt("0x10000000000000000")), want: sortedbytes.KindBigInt},
+			{b: sortedbytes.AppendBigInt(nil, mustParseBigInt("-0x10000000000000000")), want: sortedbytes.KindBigInt},
+			{b: sortedbytes.AppendFloat32(nil, 0), want: sortedbytes.KindFloat32},
+			{b: sortedbytes.AppendFloat64(nil, 0), want: sortedbytes.KindFloat64},
+			{b: sortedbytes.AppendBool(nil, false), want: sortedbytes.KindBool},
+			{b: sortedbytes.AppendBool(nil, true), want: sortedbytes.KindBool},
+			{b: sortedbytes.AppendUUID(nil, sortedbytes.UUID{}), want: sortedbytes.KindUUID},
+			{b: sortedbytes.AppendVersionstamp(nil, sortedbytes.Versionstamp{}), want: sortedbytes.KindVersionstamp},
+			{b: sortedbytes.AppendTime(nil, time.Unix(0, 0)), want: sortedbytes.KindTime},
+			{b: sortedbytes.AppendBytesDesc(nil, nil), want: sortedbytes.KindBytesDesc},
+			{b: sortedbytes.AppendStringDesc(nil, ""), want: sortedbytes.KindStringDesc},
+			{b: sortedbytes.AppendInt32Desc(nil, -1), want: sortedbytes.KindInt32Desc},
+			{b: sortedbytes.AppendInt64Desc(nil, 1), want: sortedbytes.KindInt64Desc},
+			{b: sortedbytes.AppendVarIntDesc(nil, 1), want: sortedbytes.KindIntDesc},
+			{b: sortedbytes.AppendBigIntDesc(nil, big.NewInt(0)), want: sortedbytes.KindIntDesc},
+			{b: sortedbytes.AppendBigIntDesc(nil, mustParseBigInt("0x10000000000000000")), want: sortedbytes.KindBigIntDesc},
+			{b: sortedbytes.AppendBigIntDesc(nil, mustParseBigInt("-0x10000000000000000")), want: sortedbytes.KindBigIntDesc},
+			{b: sortedbytes.AppendFloat32Desc(nil, 0), want: sortedbytes.KindFloat32Desc},
+			{b: sortedbytes.AppendFloat64Desc(nil, 0), want: sortedbytes.KindFloat64Desc},
+			{b: sortedbytes.AppendBoolDesc(nil, true), want: sortedbytes.KindBoolDesc},
+			{b: sortedbytes.AppendUUIDDesc(nil, sortedbytes.UUID{}), want: sortedbytes.KindUUIDDesc},
+			{b: sortedbytes.AppendTimeDesc(nil, time.Unix(0, 0)), want: sortedbytes.KindTimeDesc},
+		}
+		for i, tc := range testCases {
+			got, err := sortedbytes.PeekKind(tc.b)
+			if err != nil {
+				t.Errorf("case %d: got error: %s", i, err)
+				continue
+			}
+			if got != tc.want {
+				t.Errorf("case %d: kind unmatch: got=%s, want=%s", i, got, tc.want)
+			}
+			if got.Desc() != (tc.want != sortedbytes.KindNull && tc.b[0] > 0x40) {
+				t.Errorf("case %d: desc unmatch: got=%v, kind=%s", i, got.Desc(), got)
+			}
+		}
+	})
+	t.Run("int", func(t *testing.T) {
+		// Each integer kind is decoded by the Take functions which its
+		// comment lists, and not by the other fixed size ones.
+		testCases := []int64{
+			math.MinInt64, -1 << 40, math.MinInt32, -0x100, -1,
+			0, 1, 0x100, math.MaxInt32, 1 << 40, math.MaxInt64,
+		}
+		for i, v := range testCases {
+			inputs := [][]byte{sortedbytes.AppendVarInt(nil, v), sortedbytes.AppendInt64(nil, v)}
+			if int64(int32(v)) == v {
+				inputs = append(inputs, sortedbytes.AppendInt32(nil, int32(v)))
+			}
+			for _, b := range inputs {
+				kind, err := sortedbytes.PeekKind(b)
+				if err != nil {
+					t.Fatalf("case %d: 0x%x: got error: %s", i, b, err)
+				}
+				_, _, err32 := sortedbytes.TakeInt32(b)
+				_, _, err64 := sortedbytes.TakeInt64(b)
+				_, _, errVar := sortedbytes.TakeVarInt(b)
+				_, _, errBig := sortedbytes.TakeBigInt(b)
+				if errVar != nil || errBig != nil {
+					t.Errorf("case %d: 0x%x: TakeVarInt or TakeBigInt got error: %v, %v", i, b, errVar, errBig)
+				}
+				if got, want := err32 == nil, kind == sortedbytes.KindInt32 || v == 0; got != want {
+					t.Errorf("case %d: 0x%x: kind=%s, TakeInt32 error %v", i, b, kind, err32)
+				}
+				if got, want := err64 == nil, kind == sortedbytes.KindInt64 || v == 0; got != want {
+					t.Errorf("case %d: 0x%x: kind=%s, TakeInt64 error %v", i, b, kind, err64)
+				}
+			}
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		testCases := [][]byte{
+			nil,
+			[]byte("\x03"),
+			[]byte("\x41"),
+			[]byte("\xcc"),
+			[]byte("\xff"),
+		}
+		for i, input := range testCases {
+			got, err := sortedbytes.PeekKind(input)
+			if err == nil {
+				t.Errorf("case %d: got no error", i)
+			}
+			if got != sortedbytes.KindInvalid {
+				t.Errorf("case %d: kind unmatch: got=%s, want=%s", i, got, sortedbytes.KindInvalid)
+			}
+		}
+	})
+}
+
+func TestKindString(t *testing.T) {
+	testCases := []struct {
+		k    sortedbytes.Kind
+		want string
+	}{
+		{k: sortedbytes.KindInvalid, want: "invalid"},
+		{k: sortedbytes.KindInt, want: "int"},
+		{k: sortedbytes.KindInt64Desc, want: "int64 desc"},
+		{k: sortedbytes.KindTimeDesc, want: "time desc"},
+		{k: sortedbytes.Kind(-1), want: "invalid"},
+		{k: sortedbytes.Kind(100), want: "invalid"},
+	}
+	for i, tc := range testCases {
+		if got := tc.k.String(); got != tc.want {
+			t.Errorf("case %d: string unmatch: got=%s, want=%s", i, got, tc.want)
+		}
+	}
+}
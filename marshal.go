@@ -0,0 +1,745 @@
+package sortedbytes
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hnakamur/sortedbytes/internal/structtag"
+)
+
+// Marshal returns the encoded key of v, which must be a struct or
+// a pointer to a struct.
+//
+// Exported fields are encoded in the declaration order with the Append
+// function for their types. The field tag "sortedbytes" changes how
+// a field is encoded:
+//
+//     // Field is ignored.
+//     Field int64 `sortedbytes:"-"`
+//
+//     // Field is encoded in the descending order.
+//     Field int64 `sortedbytes:",desc"`
+//
+//     // Field is encoded at position 2 in the key.
+//     Field int64 `sortedbytes:"2"`
+//
+//     // Field is encoded at position 2 in the key in the descending order.
+//     Field int64 `sortedbytes:"2,desc"`
+//
+// Fields are encoded in the ascending order of their positions. Either all
+// or none of the encoded fields must have positions, and positions must
+// not be duplicated.
+//
+// Supported field types are bool, int, int8, int16, int32, int64, uint,
+// uint8, uint16, uint32, uint64, float32, float64, string, []byte,
+// *big.Int, time.Time, sql.NullBool, sql.NullInt32, sql.NullInt64,
+// sql.NullFloat64, sql.NullString, sql.NullTime, NullUint32, NullUint64,
+// NullFloat32, NullBytes, UUID, and NullUUID. Types with those underlying basic types are also supported.
+// int, int8, and int16 are encoded as int64, int32, and int32 respectively,
+// and uint, uint8, and uint16 are encoded as uint64, uint32, and uint32
+// respectively.
+//
+// Marshal returns an error if a *big.Int field is nil or its absolute value
+// needs more than 255 bytes.
+func Marshal(v interface{}) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("sortedbytes: Marshal of non-struct type %T", v)
+	}
+	fields, err := cachedStructFields(rv.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	var dst []byte
+	for _, f := range fields {
+		fv := rv.Field(f.index)
+		if f.codec.check != nil {
+			if err := f.codec.check(fv); err != nil {
+				return nil, fmt.Errorf("sortedbytes: field %s: %w", f.name, err)
+			}
+		}
+		if f.desc {
+			dst = f.codec.appendDesc(dst, fv)
+		} else {
+			dst = f.codec.append(dst, fv)
+		}
+	}
+	return dst, nil
+}
+
+// Unmarshal decodes b into the struct pointed to by v.
+// See Marshal for how fields are encoded.
+//
+// b must contain only the encoded fields of the struct.
+//
+// If a field fails to be decoded, the returned error wraps a *DecodeError
+// whose Offset is the offset in b, which you can get with errors.As.
+func Unmarshal(b []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("sortedbytes: Unmarshal of non-pointer to struct type %T", v)
+	}
+	rv = rv.Elem()
+	fields, err := cachedStructFields(rv.Type())
+	if err != nil {
+		return err
+	}
+
+	rest := b
+	for _, f := range fields {
+		fv := rv.Field(f.index)
+		var r []byte
+		if f.desc {
+			r, err = f.codec.takeDesc(rest, fv)
+		} else {
+			r, err = f.codec.take(rest, fv)
+		}
+		if err != nil {
+			err = AddOffset(newDecodeError(rest, err, nil), len(b)-len(rest))
+			return fmt.Errorf("%w (field %s)", err, f.name)
+		}
+		rest = r
+	}
+	if len(rest) > 0 {
+		return &DecodeError{
+			Offset:   len(b) - len(rest),
+			TypeCode: rest[0],
+			Err:      ErrTrailingBytes,
+		}
+	}
+	return nil
+}
+
+type structField struct {
+	name     string
+	index    int
+	position int
+	desc     bool
+	codec    *fieldCodec
+}
+
+type fieldCodec struct {
+	// check reports an error if v cannot be encoded. It is nil if all
+	// values of the type can be encoded.
+	check      func(v reflect.Value) error
+	append     func(dst []byte, v reflect.Value) []byte
+	appendDesc func(dst []byte, v reflect.Value) []byte
+	take       func(b []byte, v reflect.Value) ([]byte, error)
+	takeDesc   func(b []byte, v reflect.Value) ([]byte, error)
+}
+
+type structFieldsResult struct {
+	fields []structField
+	err    error
+}
+
+var structFieldsCache sync.Map // map[reflect.Type]structFieldsResult
+
+func cachedStructFields(t reflect.Type) ([]structField, error) {
+	if r, ok := structFieldsCache.Load(t); ok {
+		r := r.(structFieldsResult)
+		return r.fields, r.err
+	}
+	fields, err := typeStructFields(t)
+	structFieldsCache.Store(t, structFieldsResult{fields: fields, err: err})
+	return fields, err
+}
+
+func typeStructFields(t reflect.Type) ([]structField, error) {
+	var fields []structField
+	withPosition := 0
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		tag, err := structtag.Parse(sf.Tag.Get(structtag.Name))
+		if err != nil {
+			return nil, fmt.Errorf("sortedbytes: field %s: %w", sf.Name, err)
+		}
+		if tag.Skip {
+			continue
+		}
+		if tag.HasPosition {
+			withPosition++
+		}
+		codec := codecForType(sf.Type)
+		if codec == nil {
+			return nil, fmt.Errorf("sortedbytes: field %s: unsupported type %s", sf.Name, sf.Type)
+		}
+		fields = append(fields, structField{
+			name:     sf.Name,
+			index:    i,
+			position: tag.Position,
+			desc:     tag.Desc,
+			codec:    codec,
+		})
+	}
+
+	if withPosition == 0 {
+		return fields, nil
+	}
+	if withPosition != len(fields) {
+		return nil, fmt.Errorf("sortedbytes: type %s: either all or none of fields must have positions", t)
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].position < fields[j].position
+	})
+	for i := 1; i < len(fields); i++ {
+		if fields[i-1].position == fields[i].position {
+			return nil, fmt.Errorf("sortedbytes: type %s: duplicate position %d for fields %s and %s",
+				t, fields[i].position, fields[i-1].name, fields[i].name)
+		}
+	}
+	return fields, nil
+}
+
+var (
+	bytesType       = reflect.TypeOf([]byte(nil))
+	bigIntType      = reflect.TypeOf((*big.Int)(nil))
+	timeType        = reflect.TypeOf(time.Time{})
+	nullBoolType    = reflect.TypeOf(sql.NullBool{})
+	nullInt32Type   = reflect.TypeOf(sql.NullInt32{})
+	nullInt64Type   = reflect.TypeOf(sql.NullInt64{})
+	nullFloat64Type = reflect.TypeOf(sql.NullFloat64{})
+	nullStringType  = reflect.TypeOf(sql.NullString{})
+	nullTimeType    = reflect.TypeOf(sql.NullTime{})
+	nullUint32Type  = reflect.TypeOf(NullUint32{})
+	nullUint64Type  = reflect.TypeOf(NullUint64{})
+	nullBytesType   = reflect.TypeOf(NullBytes{})
+	uuidType        = reflect.TypeOf(UUID{})
+	nullUUIDType    = reflect.TypeOf(NullUUID{})
+	nullFloat32Type = reflect.TypeOf(NullFloat32{})
+)
+
+func codecForType(t reflect.Type) *fieldCodec {
+	switch t {
+	case bytesType:
+		return bytesCodec
+	case bigIntType:
+		return bigIntCodec
+	case timeType:
+		return timeCodec
+	case nullBoolType:
+		return nullBoolCodec
+	case nullInt32Type:
+		return nullInt32Codec
+	case nullInt64Type:
+		return nullInt64Codec
+	case nullFloat64Type:
+		return nullFloat64Codec
+	case nullStringType:
+		return nullStringCodec
+	case nullTimeType:
+		return nullTimeCodec
+	case nullUint32Type:
+		return nullUint32Codec
+	case nullUint64Type:
+		return nullUint64Codec
+	case nullBytesType:
+		return nullBytesCodec
+	case uuidType:
+		return uuidCodec
+	case nullUUIDType:
+		return nullUUIDCodec
+	case nullFloat32Type:
+		return nullFloat32Codec
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return boolCodec
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return int32Codec
+	case reflect.Int, reflect.Int64:
+		return int64Codec
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return uint32Codec
+	case reflect.Uint, reflect.Uint64:
+		return uint64Codec
+	case reflect.Float32:
+		return float32Codec
+	case reflect.Float64:
+		return float64Codec
+	case reflect.String:
+		return stringCodec
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return bytesCodec
+		}
+	}
+	return nil
+}
+
+var boolCodec = &fieldCodec{
+	append: func(dst []byte, v reflect.Value) []byte {
+		return AppendBool(dst, v.Bool())
+	},
+	appendDesc: func(dst []byte, v reflect.Value) []byte {
+		return AppendBoolDesc(dst, v.Bool())
+	},
+	take: func(b []byte, v reflect.Value) ([]byte, error) {
+		return setBool(v)(TakeBool(b))
+	},
+	takeDesc: func(b []byte, v reflect.Value) ([]byte, error) {
+		return setBool(v)(TakeBoolDesc(b))
+	},
+}
+
+func setBool(v reflect.Value) func(bool, []byte, error) ([]byte, error) {
+	return func(x bool, rest []byte, err error) ([]byte, error) {
+		if err != nil {
+			return nil, err
+		}
+		v.SetBool(x)
+		return rest, nil
+	}
+}
+
+var int32Codec = &fieldCodec{
+	append: func(dst []byte, v reflect.Value) []byte {
+		return AppendInt32(dst, int32(v.Int()))
+	},
+	appendDesc: func(dst []byte, v reflect.Value) []byte {
+		return AppendInt32Desc(dst, int32(v.Int()))
+	},
+	take: func(b []byte, v reflect.Value) ([]byte, error) {
+		x, rest, err := TakeInt32(b)
+		return setInt(v)(int64(x), rest, err)
+	},
+	takeDesc: func(b []byte, v reflect.Value) ([]byte, error) {
+		x, rest, err := TakeInt32Desc(b)
+		return setInt(v)(int64(x), rest, err)
+	},
+}
+
+var int64Codec = &fieldCodec{
+	append: func(dst []byte, v reflect.Value) []byte {
+		return AppendInt64(dst, v.Int())
+	},
+	appendDesc: func(dst []byte, v reflect.Value) []byte {
+		return AppendInt64Desc(dst, v.Int())
+	},
+	take: func(b []byte, v reflect.Value) ([]byte, error) {
+		return setInt(v)(TakeInt64(b))
+	},
+	takeDesc: func(b []byte, v reflect.Value) ([]byte, error) {
+		return setInt(v)(TakeInt64Desc(b))
+	},
+}
+
+func setInt(v reflect.Value) func(int64, []byte, error) ([]byte, error) {
+	return func(x int64, rest []byte, err error) ([]byte, error) {
+		if err != nil {
+			return nil, err
+		}
+		if v.OverflowInt(x) {
+			return nil, ErrValueOutOfRange
+		}
+		v.SetInt(x)
+		return rest, nil
+	}
+}
+
+var uint32Codec = &fieldCodec{
+	append: func(dst []byte, v reflect.Value) []byte {
+		return AppendUint32(dst, uint32(v.Uint()))
+	},
+	appendDesc: func(dst []byte, v reflect.Value) []byte {
+		return AppendUint32Desc(dst, uint32(v.Uint()))
+	},
+	take: func(b []byte, v reflect.Value) ([]byte, error) {
+		x, rest, err := TakeUint32(b)
+		return setUint(v)(uint64(x), rest, err)
+	},
+	takeDesc: func(b []byte, v reflect.Value) ([]byte, error) {
+		x, rest, err := TakeUint32Desc(b)
+		return setUint(v)(uint64(x), rest, err)
+	},
+}
+
+var uint64Codec = &fieldCodec{
+	append: func(dst []byte, v reflect.Value) []byte {
+		return AppendUint64(dst, v.Uint())
+	},
+	appendDesc: func(dst []byte, v reflect.Value) []byte {
+		return AppendUint64Desc(dst, v.Uint())
+	},
+	take: func(b []byte, v reflect.Value) ([]byte, error) {
+		return setUint(v)(TakeUint64(b))
+	},
+	takeDesc: func(b []byte, v reflect.Value) ([]byte, error) {
+		return setUint(v)(TakeUint64Desc(b))
+	},
+}
+
+func setUint(v reflect.Value) func(uint64, []byte, error) ([]byte, error) {
+	return func(x uint64, rest []byte, err error) ([]byte, error) {
+		if err != nil {
+			return nil, err
+		}
+		if v.OverflowUint(x) {
+			return nil, ErrValueOutOfRange
+		}
+		v.SetUint(x)
+		return rest, nil
+	}
+}
+
+var float32Codec = &fieldCodec{
+	append: func(dst []byte, v reflect.Value) []byte {
+		return AppendFloat32(dst, float32(v.Float()))
+	},
+	appendDesc: func(dst []byte, v reflect.Value) []byte {
+		return AppendFloat32Desc(dst, float32(v.Float()))
+	},
+	take: func(b []byte, v reflect.Value) ([]byte, error) {
+		x, rest, err := TakeFloat32(b)
+		return setFloat(v)(float64(x), rest, err)
+	},
+	takeDesc: func(b []byte, v reflect.Value) ([]byte, error) {
+		x, rest, err := TakeFloat32Desc(b)
+		return setFloat(v)(float64(x), rest, err)
+	},
+}
+
+var float64Codec = &fieldCodec{
+	append: func(dst []byte, v reflect.Value) []byte {
+		return AppendFloat64(dst, v.Float())
+	},
+	appendDesc: func(dst []byte, v reflect.Value) []byte {
+		return AppendFloat64Desc(dst, v.Float())
+	},
+	take: func(b []byte, v reflect.Value) ([]byte, error) {
+		return setFloat(v)(TakeFloat64(b))
+	},
+	takeDesc: func(b []byte, v reflect.Value) ([]byte, error) {
+		return setFloat(v)(TakeFloat64Desc(b))
+	},
+}
+
+func setFloat(v reflect.Value) func(float64, []byte, error) ([]byte, error) {
+	return func(x float64, rest []byte, err error) ([]byte, error) {
+		if err != nil {
+			return nil, err
+		}
+		v.SetFloat(x)
+		return rest, nil
+	}
+}
+
+var stringCodec = &fieldCodec{
+	append: func(dst []byte, v reflect.Value) []byte {
+		return AppendString(dst, v.String())
+	},
+	appendDesc: func(dst []byte, v reflect.Value) []byte {
+		return AppendStringDesc(dst, v.String())
+	},
+	take: func(b []byte, v reflect.Value) ([]byte, error) {
+		return setString(v)(TakeString(b))
+	},
+	takeDesc: func(b []byte, v reflect.Value) ([]byte, error) {
+		return setString(v)(TakeStringDesc(b))
+	},
+}
+
+func setString(v reflect.Value) func(string, []byte, error) ([]byte, error) {
+	return func(x string, rest []byte, err error) ([]byte, error) {
+		if err != nil {
+			return nil, err
+		}
+		v.SetString(x)
+		return rest, nil
+	}
+}
+
+var bytesCodec = &fieldCodec{
+	append: func(dst []byte, v reflect.Value) []byte {
+		return AppendBytes(dst, v.Bytes())
+	},
+	appendDesc: func(dst []byte, v reflect.Value) []byte {
+		return AppendBytesDesc(dst, v.Bytes())
+	},
+	take: func(b []byte, v reflect.Value) ([]byte, error) {
+		return setBytes(v)(TakeBytes(b))
+	},
+	takeDesc: func(b []byte, v reflect.Value) ([]byte, error) {
+		return setBytes(v)(TakeBytesDesc(b))
+	},
+}
+
+func setBytes(v reflect.Value) func([]byte, []byte, error) ([]byte, error) {
+	return func(x []byte, rest []byte, err error) ([]byte, error) {
+		if err != nil {
+			return nil, err
+		}
+		v.SetBytes(x)
+		return rest, nil
+	}
+}
+
+func setValue(v, x reflect.Value, rest []byte, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	v.Set(x)
+	return rest, nil
+}
+
+var bigIntCodec = &fieldCodec{
+	check: func(v reflect.Value) error {
+		x := v.Interface().(*big.Int)
+		if x == nil {
+			return errors.New("nil *big.Int")
+		}
+		if x.BitLen() > 8*maxBigIntBytes {
+			return ErrValueOutOfRange
+		}
+		return nil
+	},
+	append: func(dst []byte, v reflect.Value) []byte {
+		return AppendBigInt(dst, v.Interface().(*big.Int))
+	},
+	appendDesc: func(dst []byte, v reflect.Value) []byte {
+		return AppendBigIntDesc(dst, v.Interface().(*big.Int))
+	},
+	take: func(b []byte, v reflect.Value) ([]byte, error) {
+		x, rest, err := TakeBigInt(b)
+		return setValue(v, reflect.ValueOf(x), rest, err)
+	},
+	takeDesc: func(b []byte, v reflect.Value) ([]byte, error) {
+		x, rest, err := TakeBigIntDesc(b)
+		return setValue(v, reflect.ValueOf(x), rest, err)
+	},
+}
+
+var timeCodec = &fieldCodec{
+	append: func(dst []byte, v reflect.Value) []byte {
+		return AppendTime(dst, v.Interface().(time.Time))
+	},
+	appendDesc: func(dst []byte, v reflect.Value) []byte {
+		return AppendTimeDesc(dst, v.Interface().(time.Time))
+	},
+	take: func(b []byte, v reflect.Value) ([]byte, error) {
+		x, rest, err := TakeTime(b)
+		return setValue(v, reflect.ValueOf(x), rest, err)
+	},
+	takeDesc: func(b []byte, v reflect.Value) ([]byte, error) {
+		x, rest, err := TakeTimeDesc(b)
+		return setValue(v, reflect.ValueOf(x), rest, err)
+	},
+}
+
+var uuidCodec = &fieldCodec{
+	append: func(dst []byte, v reflect.Value) []byte {
+		return AppendUUID(dst, v.Interface().(UUID))
+	},
+	appendDesc: func(dst []byte, v reflect.Value) []byte {
+		return AppendUUIDDesc(dst, v.Interface().(UUID))
+	},
+	take: func(b []byte, v reflect.Value) ([]byte, error) {
+		x, rest, err := TakeUUID(b)
+		return setValue(v, reflect.ValueOf(x), rest, err)
+	},
+	takeDesc: func(b []byte, v reflect.Value) ([]byte, error) {
+		x, rest, err := TakeUUIDDesc(b)
+		return setValue(v, reflect.ValueOf(x), rest, err)
+	},
+}
+
+var nullBoolCodec = &fieldCodec{
+	append: func(dst []byte, v reflect.Value) []byte {
+		return AppendNullBool(dst, v.Interface().(sql.NullBool))
+	},
+	appendDesc: func(dst []byte, v reflect.Value) []byte {
+		return AppendNullBoolDesc(dst, v.Interface().(sql.NullBool))
+	},
+	take: func(b []byte, v reflect.Value) ([]byte, error) {
+		x, rest, err := TakeNullBool(b)
+		return setValue(v, reflect.ValueOf(x), rest, err)
+	},
+	takeDesc: func(b []byte, v reflect.Value) ([]byte, error) {
+		x, rest, err := TakeNullBoolDesc(b)
+		return setValue(v, reflect.ValueOf(x), rest, err)
+	},
+}
+
+var nullInt32Codec = &fieldCodec{
+	append: func(dst []byte, v reflect.Value) []byte {
+		return AppendNullInt32(dst, v.Interface().(sql.NullInt32))
+	},
+	appendDesc: func(dst []byte, v reflect.Value) []byte {
+		return AppendNullInt32Desc(dst, v.Interface().(sql.NullInt32))
+	},
+	take: func(b []byte, v reflect.Value) ([]byte, error) {
+		x, rest, err := TakeNullInt32(b)
+		return setValue(v, reflect.ValueOf(x), rest, err)
+	},
+	takeDesc: func(b []byte, v reflect.Value) ([]byte, error) {
+		x, rest, err := TakeNullInt32Desc(b)
+		return setValue(v, reflect.ValueOf(x), rest, err)
+	},
+}
+
+var nullInt64Codec = &fieldCodec{
+	append: func(dst []byte, v reflect.Value) []byte {
+		return AppendNullInt64(dst, v.Interface().(sql.NullInt64))
+	},
+	appendDesc: func(dst []byte, v reflect.Value) []byte {
+		return AppendNullInt64Desc(dst, v.Interface().(sql.NullInt64))
+	},
+	take: func(b []byte, v reflect.Value) ([]byte, error) {
+		x, rest, err := TakeNullInt64(b)
+		return setValue(v, reflect.ValueOf(x), rest, err)
+	},
+	takeDesc: func(b []byte, v reflect.Value) ([]byte, error) {
+		x, rest, err := TakeNullInt64Desc(b)
+		return setValue(v, reflect.ValueOf(x), rest, err)
+	},
+}
+
+var nullFloat64Codec = &fieldCodec{
+	append: func(dst []byte, v reflect.Value) []byte {
+		return AppendNullFloat64(dst, v.Interface().(sql.NullFloat64))
+	},
+	appendDesc: func(dst []byte, v reflect.Value) []byte {
+		return AppendNullFloat64Desc(dst, v.Interface().(sql.NullFloat64))
+	},
+	take: func(b []byte, v reflect.Value) ([]byte, error) {
+		x, rest, err := TakeNullFloat64(b)
+		return setValue(v, reflect.ValueOf(x), rest, err)
+	},
+	takeDesc: func(b []byte, v reflect.Value) ([]byte, error) {
+		x, rest, err := TakeNullFloat64Desc(b)
+		return setValue(v, reflect.ValueOf(x), rest, err)
+	},
+}
+
+var nullStringCodec = &fieldCodec{
+	append: func(dst []byte, v reflect.Value) []byte {
+		return AppendNullString(dst, v.Interface().(sql.NullString))
+	},
+	appendDesc: func(dst []byte, v reflect.Value) []byte {
+		return AppendNullStringDesc(dst, v.Interface().(sql.NullString))
+	},
+	take: func(b []byte, v reflect.Value) ([]byte, error) {
+		x, rest, err := TakeNullString(b)
+		return setValue(v, reflect.ValueOf(x), rest, err)
+	},
+	takeDesc: func(b []byte, v reflect.Value) ([]byte, error) {
+		x, rest, err := TakeNullStringDesc(b)
+		return setValue(v, reflect.ValueOf(x), rest, err)
+	},
+}
+
+var nullTimeCodec = &fieldCodec{
+	append: func(dst []byte, v reflect.Value) []byte {
+		return AppendNullTime(dst, v.Interface().(sql.NullTime))
+	},
+	appendDesc: func(dst []byte, v reflect.Value) []byte {
+		return AppendNullTimeDesc(dst, v.Interface().(sql.NullTime))
+	},
+	take: func(b []byte, v reflect.Value) ([]byte, error) {
+		x, rest, err := TakeNullTime(b)
+		return setValue(v, reflect.ValueOf(x), rest, err)
+	},
+	takeDesc: func(b []byte, v reflect.Value) ([]byte, error) {
+		x, rest, err := TakeNullTimeDesc(b)
+		return setValue(v, reflect.ValueOf(x), rest, err)
+	},
+}
+
+var nullUint32Codec = &fieldCodec{
+	append: func(dst []byte, v reflect.Value) []byte {
+		return AppendNullUint32(dst, v.Interface().(NullUint32))
+	},
+	appendDesc: func(dst []byte, v reflect.Value) []byte {
+		return AppendNullUint32Desc(dst, v.Interface().(NullUint32))
+	},
+	take: func(b []byte, v reflect.Value) ([]byte, error) {
+		x, rest, err := TakeNullUint32(b)
+		return setValue(v, reflect.ValueOf(x), rest, err)
+	},
+	takeDesc: func(b []byte, v reflect.Value) ([]byte, error) {
+		x, rest, err := TakeNullUint32Desc(b)
+		return setValue(v, reflect.ValueOf(x), rest, err)
+	},
+}
+
+var nullUint64Codec = &fieldCodec{
+	append: func(dst []byte, v reflect.Value) []byte {
+		return AppendNullUint64(dst, v.Interface().(NullUint64))
+	},
+	appendDesc: func(dst []byte, v reflect.Value) []byte {
+		return AppendNullUint64Desc(dst, v.Interface().(NullUint64))
+	},
+	take: func(b []byte, v reflect.Value) ([]byte, error) {
+		x, rest, err := TakeNullUint64(b)
+		return setValue(v, reflect.ValueOf(x), rest, err)
+	},
+	takeDesc: func(b []byte, v reflect.Value) ([]byte, error) {
+		x, rest, err := TakeNullUint64Desc(b)
+		return setValue(v, reflect.ValueOf(x), rest, err)
+	},
+}
+
+var nullBytesCodec = &fieldCodec{
+	append: func(dst []byte, v reflect.Value) []byte {
+		return AppendNullBytes(dst, v.Interface().(NullBytes))
+	},
+	appendDesc: func(dst []byte, v reflect.Value) []byte {
+		return AppendNullBytesDesc(dst, v.Interface().(NullBytes))
+	},
+	take: func(b []byte, v reflect.Value) ([]byte, error) {
+		x, rest, err := TakeNullBytes(b)
+		return setValue(v, reflect.ValueOf(x), rest, err)
+	},
+	takeDesc: func(b []byte, v reflect.Value) ([]byte, error) {
+		x, rest, err := TakeNullBytesDesc(b)
+		return setValue(v, reflect.ValueOf(x), rest, err)
+	},
+}
+
+var nullUUIDCodec = &fieldCodec{
+	append: func(dst []byte, v reflect.Value) []byte {
+		return AppendNullUUID(dst, v.Interface().(NullUUID))
+	},
+	appendDesc: func(dst []byte, v reflect.Value) []byte {
+		return AppendNullUUIDDesc(dst, v.Interface().(NullUUID))
+	},
+	take: func(b []byte, v reflect.Value) ([]byte, error) {
+		x, rest, err := TakeNullUUID(b)
+		return setValue(v, reflect.ValueOf(x), rest, err)
+	},
+	takeDesc: func(b []byte, v reflect.Value) ([]byte, error) {
+		x, rest, err := TakeNullUUIDDesc(b)
+		return setValue(v, reflect.ValueOf(x), rest, err)
+	},
+}
+
+var nullFloat32Codec = &fieldCodec{
+	append: func(dst []byte, v reflect.Value) []byte {
+		return AppendNullFloat32(dst, v.Interface().(NullFloat32))
+	},
+	appendDesc: func(dst []byte, v reflect.Value) []byte {
+		return AppendNullFloat32Desc(dst, v.Interface().(NullFloat32))
+	},
+	take: func(b []byte, v reflect.Value) ([]byte, error) {
+		x, rest, err := TakeNullFloat32(b)
+		return setValue(v, reflect.ValueOf(x), rest, err)
+	},
+	takeDesc: func(b []byte, v reflect.Value) ([]byte, error) {
+		x, rest, err := TakeNullFloat32Desc(b)
+		return setValue(v, reflect.ValueOf(x), rest, err)
+	},
+}
@@ -0,0 +1,249 @@
+package sortedbytes_test
+
+import (
+	"bytes"
+	"database/sql"
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hnakamur/sortedbytes"
+)
+
+type marshalAllTypes struct {
+	Bool        bool
+	Int         int
+	Int8        int8
+	Int16       int16
+	Int32       int32
+	Int64       int64
+	Uint        uint
+	Uint8       uint8
+	Uint16      uint16
+	Uint32      uint32
+	Uint64      uint64
+	Float32     float32
+	Float64     float64
+	String      string
+	Bytes       []byte
+	BigInt      *big.Int
+	Time        time.Time
+	NullBool    sql.NullBool
+	NullInt32   sql.NullInt32
+	NullInt64   sql.NullInt64
+	NullFloat64 sql.NullFloat64
+	NullString  sql.NullString
+	NullTime    sql.NullTime
+	NullUint32  sortedbytes.NullUint32
+	NullUint64  sortedbytes.NullUint64
+	NullBytes   sortedbytes.NullBytes
+	UUID        sortedbytes.UUID
+	NullUUID    sortedbytes.NullUUID
+	NullFloat32 sortedbytes.NullFloat32
+}
+
+type userID int64
+
+type marshalKey struct {
+	TenantID   string
+	UserID     userID
+	CreatedAt  time.Time `sortedbytes:",desc"`
+	Comment    string    `sortedbytes:"-"`
+	unexported int
+}
+
+type marshalPositionKey struct {
+	C sql.NullString `sortedbytes:"3,desc"`
+	A int32          `sortedbytes:"1"`
+	B uint64         `sortedbytes:"2"`
+}
+
+func TestMarshal(t *testing.T) {
+	t.Run("sameAsAppend", func(t *testing.T) {
+		createdAt := time.Date(2020, 5, 17, 12, 34, 56, 0, time.UTC)
+		got, err := sortedbytes.Marshal(marshalKey{
+			TenantID:   "foo",
+			UserID:     123,
+			CreatedAt:  createdAt,
+			Comment:    "ignored",
+			unexported: 1,
+		})
+		if err != nil {
+			t.Fatalf("got error: %s", err)
+		}
+		want := sortedbytes.AppendString([]byte(nil), "foo")
+		want = sortedbytes.AppendInt64(want, 123)
+		want = sortedbytes.AppendTimeDesc(want, createdAt)
+		if !bytes.Equal(got, want) {
+			t.Errorf("encoded bytes unmatch: got=0x%x, want=0x%x", got, want)
+		}
+	})
+	t.Run("position", func(t *testing.T) {
+		got, err := sortedbytes.Marshal(&marshalPositionKey{
+			A: -1,
+			B: 2,
+			C: sql.NullString{Valid: true, String: "c"},
+		})
+		if err != nil {
+			t.Fatalf("got error: %s", err)
+		}
+		want := sortedbytes.AppendInt32([]byte(nil), -1)
+		want = sortedbytes.AppendUint64(want, 2)
+		want = sortedbytes.AppendNullStringDesc(want, sql.NullString{Valid: true, String: "c"})
+		if !bytes.Equal(got, want) {
+			t.Errorf("encoded bytes unmatch: got=0x%x, want=0x%x", got, want)
+		}
+	})
+	t.Run("order", func(t *testing.T) {
+		testCases := []marshalKey{
+			{TenantID: "a", UserID: 1, CreatedAt: time.Unix(2, 0)},
+			{TenantID: "a", UserID: 1, CreatedAt: time.Unix(1, 0)},
+			{TenantID: "a", UserID: 2, CreatedAt: time.Unix(3, 0)},
+			{TenantID: "b", UserID: -1, CreatedAt: time.Unix(3, 0)},
+		}
+		for i := 1; i < len(testCases); i++ {
+			a, err := sortedbytes.Marshal(testCases[i-1])
+			if err != nil {
+				t.Fatalf("case %d: got error: %s", i, err)
+			}
+			b, err := sortedbytes.Marshal(testCases[i])
+			if err != nil {
+				t.Fatalf("case %d: got error: %s", i, err)
+			}
+			if got, want := bytes.Compare(a, b), -1; got != want {
+				t.Errorf("case %d: compare result unmatch: got=%d, want=%d, a=0x%x, b=0x%x",
+					i, got, want, a, b)
+			}
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		testCases := []interface{}{
+			1,
+			(*marshalKey)(nil),
+			struct{ A []int }{},
+			struct {
+				A int `sortedbytes:"x"`
+			}{},
+			struct {
+				A int `sortedbytes:",asc"`
+			}{},
+			struct {
+				A int `sortedbytes:"1"`
+				B int
+			}{},
+			struct {
+				A int `sortedbytes:"1"`
+				B int `sortedbytes:"1"`
+			}{},
+			struct {
+				A int64
+				B *big.Int
+			}{A: 1},
+			struct {
+				A *big.Int `sortedbytes:",desc"`
+			}{},
+			struct{ A *big.Int }{A: new(big.Int).Lsh(big.NewInt(1), 8*255)},
+		}
+		for i, input := range testCases {
+			_, err := sortedbytes.Marshal(input)
+			if err == nil {
+				t.Errorf("case %d: got no error", i)
+			}
+		}
+	})
+}
+
+func TestUnmarshal(t *testing.T) {
+	t.Run("roundtrip", func(t *testing.T) {
+		testCases := []marshalAllTypes{
+			{
+				Bytes:  []byte{},
+				BigInt: big.NewInt(0),
+			},
+			{
+				Bool:        true,
+				Int:         math.MinInt64,
+				Int8:        math.MinInt8,
+				Int16:       math.MinInt16,
+				Int32:       math.MinInt32,
+				Int64:       math.MinInt64,
+				Uint:        math.MaxUint64,
+				Uint8:       math.MaxUint8,
+				Uint16:      math.MaxUint16,
+				Uint32:      math.MaxUint32,
+				Uint64:      math.MaxUint64,
+				Float32:     math.MaxFloat32,
+				Float64:     -1.5,
+				String:      "foo\x00",
+				Bytes:       []byte("\x00bar"),
+				BigInt:      mustParseBigInt("0x10000000000000000"),
+				Time:        time.Date(2020, 5, 17, 12, 34, 56, 789, time.UTC),
+				NullBool:    sql.NullBool{Valid: true, Bool: true},
+				NullInt32:   sql.NullInt32{Valid: true, Int32: 1},
+				NullInt64:   sql.NullInt64{Valid: true, Int64: 2},
+				NullFloat64: sql.NullFloat64{Valid: true, Float64: 3},
+				NullString:  sql.NullString{Valid: true, String: "4"},
+				NullTime:    sql.NullTime{Valid: true, Time: time.Date(2020, 5, 17, 0, 0, 0, 0, time.UTC)},
+				NullUint32:  sortedbytes.NullUint32{Valid: true, Uint32: 5},
+				NullUint64:  sortedbytes.NullUint64{Valid: true, Uint64: 6},
+				NullBytes:   sortedbytes.NullBytes{Valid: true, Bytes: []byte("7")},
+				UUID:        sortedbytes.UUID{8},
+				NullUUID:    sortedbytes.NullUUID{Valid: true, UUID: sortedbytes.UUID{15: 9}},
+				NullFloat32: sortedbytes.NullFloat32{Valid: true, Float32: 10},
+			},
+		}
+		for i, input := range testCases {
+			b, err := sortedbytes.Marshal(input)
+			if err != nil {
+				t.Fatalf("case %d: marshal: got error: %s", i, err)
+			}
+			var got marshalAllTypes
+			if err := sortedbytes.Unmarshal(b, &got); err != nil {
+				t.Fatalf("case %d: unmarshal: got error: %s", i, err)
+			}
+			if !reflect.DeepEqual(got, input) {
+				t.Errorf("case %d: value unmatch:\n got=%+v\nwant=%+v", i, got, input)
+			}
+		}
+	})
+	t.Run("desc", func(t *testing.T) {
+		input := marshalPositionKey{
+			A: 1,
+			B: 2,
+			C: sql.NullString{Valid: true, String: "c"},
+		}
+		b, err := sortedbytes.Marshal(input)
+		if err != nil {
+			t.Fatalf("marshal: got error: %s", err)
+		}
+		var got marshalPositionKey
+		if err := sortedbytes.Unmarshal(b, &got); err != nil {
+			t.Fatalf("unmarshal: got error: %s", err)
+		}
+		if got != input {
+			t.Errorf("value unmatch: got=%+v, want=%+v", got, input)
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		var k marshalKey
+		testCases := []struct {
+			b []byte
+			v interface{}
+		}{
+			{b: nil, v: k},
+			{b: nil, v: (*marshalKey)(nil)},
+			{b: []byte("\x02foo\x00"), v: &k},
+			{b: []byte("\x15\x01"), v: &k},
+			{b: append(sortedbytes.AppendNullStringDesc(sortedbytes.AppendUint64(sortedbytes.AppendInt32(nil, 1), 2), sql.NullString{}), 0x00), v: &marshalPositionKey{}},
+			{b: sortedbytes.AppendInt32(nil, math.MaxInt8+1), v: &struct{ A int8 }{}},
+		}
+		for i, tc := range testCases {
+			err := sortedbytes.Unmarshal(tc.b, tc.v)
+			if err == nil {
+				t.Errorf("case %d: got no error", i)
+			}
+		}
+	})
+}
@@ -0,0 +1,445 @@
+// Package memkv provides an in-memory ordered key-value store with
+// snapshots and transactions, so that code which stores keys encoded with
+// sortedbytes can be tested in-process.
+//
+// The store is a skiplist which keeps multiple versions of each key.
+// A Snapshot reads the versions committed before it was taken, and a Txn
+// reads from a snapshot, buffers its writes, and detects conflicts with
+// the transactions committed after its snapshot when it commits.
+//
+// The methods which take a sortedbytes.KeyRange treat a nil End as no upper
+// bound, so sortedbytes.KeyRange{} is the range of all keys. Note that
+// KeyRange.Contains reports false for any key if End is nil.
+//
+// The store keeps all versions of keys, since snapshots are not released
+// explicitly, so it is meant for tests and not for a long running process.
+package memkv
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+
+	"github.com/hnakamur/sortedbytes"
+)
+
+var (
+	// ErrConflict is returned by Txn.Commit when a key which the transaction
+	// read was written by another transaction committed after the snapshot
+	// of the transaction.
+	ErrConflict = errors.New("memkv: transaction conflict")
+
+	// ErrTxnDone is returned when a transaction is used after it was
+	// committed or discarded.
+	ErrTxnDone = errors.New("memkv: transaction already committed or discarded")
+)
+
+// Store is an in-memory ordered key-value store.
+// It is safe for concurrent use by multiple goroutines.
+type Store struct {
+	mu      sync.RWMutex
+	list    *skiplist
+	version uint64 // the version of the last commit
+	log     []commitRecord
+	active  map[*Txn]struct{}
+}
+
+// commitRecord is the keys written by a commit, which are kept while
+// a transaction which may conflict with them is active.
+type commitRecord struct {
+	version uint64
+	keys    [][]byte
+}
+
+// New returns an empty Store.
+func New() *Store {
+	return &Store{
+		list:   newSkiplist(),
+		active: make(map[*Txn]struct{}),
+	}
+}
+
+// Get returns the latest value of key and true, or false if key does not
+// exist. The caller must not modify the returned value.
+func (s *Store) Get(key []byte) (value []byte, ok bool) {
+	return s.Snapshot().Get(key)
+}
+
+// Set sets the value of key.
+func (s *Store) Set(key, value []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.commit([]write{{key: key, value: append([]byte{}, value...)}})
+}
+
+// Delete deletes key. It is not an error if key does not exist.
+func (s *Store) Delete(key []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.commit([]write{{key: key, deleted: true}})
+}
+
+// Snapshot returns a read-only view of the latest committed versions.
+func (s *Store) Snapshot() *Snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return &Snapshot{store: s, version: s.version}
+}
+
+// Begin starts a transaction which reads from the latest committed versions.
+//
+// The transaction must be finished with Commit or Discard.
+func (s *Store) Begin() *Txn {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := &Txn{
+		snap:   &Snapshot{store: s, version: s.version},
+		writes: newSkiplist(),
+		reads:  make(map[string]struct{}),
+	}
+	s.active[t] = struct{}{}
+	return t
+}
+
+type write struct {
+	key     []byte
+	value   []byte
+	deleted bool
+}
+
+// commit writes ws as a new version. s.mu must be locked.
+func (s *Store) commit(ws []write) {
+	s.version++
+	keys := make([][]byte, len(ws))
+	for i, w := range ws {
+		n := s.list.getOrInsert(w.key)
+		n.versions = append(n.versions, version{version: s.version, value: w.value, deleted: w.deleted})
+		keys[i] = n.key
+	}
+	if len(s.active) > 0 {
+		s.log = append(s.log, commitRecord{version: s.version, keys: keys})
+	}
+}
+
+// pruneLog removes the commit records which no active transaction can
+// conflict with. s.mu must be locked.
+func (s *Store) pruneLog() {
+	minVersion := s.version
+	for t := range s.active {
+		if t.snap.version < minVersion {
+			minVersion = t.snap.version
+		}
+	}
+	i := 0
+	for i < len(s.log) && s.log[i].version <= minVersion {
+		i++
+	}
+	s.log = s.log[i:]
+}
+
+// Snapshot is a read-only view of a Store at a version.
+type Snapshot struct {
+	store   *Store
+	version uint64
+}
+
+// Get returns the value of key and true, or false if key does not exist.
+// The caller must not modify the returned value.
+func (s *Snapshot) Get(key []byte) (value []byte, ok bool) {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+	n := s.store.list.get(key)
+	if n == nil {
+		return nil, false
+	}
+	v, ok := n.at(s.version)
+	if !ok || v.deleted {
+		return nil, false
+	}
+	return v.value, true
+}
+
+// Iterator returns an iterator over the keys in r in the ascending order.
+func (s *Snapshot) Iterator(r sortedbytes.KeyRange) *Iterator {
+	return &Iterator{next: skipDeleted(s.entries(r, false))}
+}
+
+// ReverseIterator returns an iterator over the keys in r in the descending
+// order.
+func (s *Snapshot) ReverseIterator(r sortedbytes.KeyRange) *Iterator {
+	return &Iterator{next: skipDeleted(s.entries(r, true))}
+}
+
+func (s *Snapshot) entries(r sortedbytes.KeyRange, reverse bool) entryFunc {
+	next := s.store.list.iterate(r, reverse, s.store.mu.RLocker())
+	return func() (entry, bool) {
+		for n := next(); n != nil; n = next() {
+			// The versions of n may be appended concurrently.
+			s.store.mu.RLock()
+			v, ok := n.at(s.version)
+			s.store.mu.RUnlock()
+			if ok {
+				return entry{key: n.key, value: v.value, deleted: v.deleted}, true
+			}
+		}
+		return entry{}, false
+	}
+}
+
+// Txn is a transaction of a Store. It is not safe for concurrent use.
+//
+// Txn satisfies the KV interface of the directory package.
+type Txn struct {
+	snap       *Snapshot
+	writes     *skiplist
+	reads      map[string]struct{}
+	readRanges []sortedbytes.KeyRange
+	done       bool
+}
+
+// Get returns the value of key and true, or false if key does not exist.
+// The caller must not modify the returned value.
+func (t *Txn) Get(key []byte) (value []byte, ok bool, err error) {
+	if t.done {
+		return nil, false, ErrTxnDone
+	}
+	if n := t.writes.get(key); n != nil {
+		v := n.versions[0]
+		return v.value, !v.deleted, nil
+	}
+	t.reads[string(key)] = struct{}{}
+	value, ok = t.snap.Get(key)
+	return value, ok, nil
+}
+
+// Set sets the value of key.
+func (t *Txn) Set(key, value []byte) error {
+	return t.write(key, append([]byte{}, value...), false)
+}
+
+// Delete deletes key. It is not an error if key does not exist.
+func (t *Txn) Delete(key []byte) error {
+	return t.write(key, nil, true)
+}
+
+func (t *Txn) write(key, value []byte, deleted bool) error {
+	if t.done {
+		return ErrTxnDone
+	}
+	n := t.writes.getOrInsert(key)
+	n.versions = append(n.versions[:0], version{value: value, deleted: deleted})
+	return nil
+}
+
+// DeleteRange deletes all keys in r.
+//
+// DeleteRange reads the keys in r, so Commit fails if another transaction
+// writes a key in r after the snapshot of t.
+func (t *Txn) DeleteRange(r sortedbytes.KeyRange) error {
+	if t.done {
+		return ErrTxnDone
+	}
+	var keys [][]byte
+	for it := t.Iterator(r); it.Next(); {
+		keys = append(keys, it.Key())
+	}
+	for _, key := range keys {
+		if err := t.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Scan calls fn for each key in r in the ascending order of keys.
+// If fn returns an error, Scan stops and returns the error.
+func (t *Txn) Scan(r sortedbytes.KeyRange, fn func(key, value []byte) error) error {
+	if t.done {
+		return ErrTxnDone
+	}
+	for it := t.Iterator(r); it.Next(); {
+		if err := fn(it.Key(), it.Value()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Iterator returns an iterator over the keys in r in the ascending order,
+// which includes the writes of t.
+//
+// The writes of t after Iterator is called may or may not be visible to
+// the iterator.
+func (t *Txn) Iterator(r sortedbytes.KeyRange) *Iterator {
+	return t.iterator(r, false)
+}
+
+// ReverseIterator returns an iterator over the keys in r in the descending
+// order, which includes the writes of t.
+func (t *Txn) ReverseIterator(r sortedbytes.KeyRange) *Iterator {
+	return t.iterator(r, true)
+}
+
+func (t *Txn) iterator(r sortedbytes.KeyRange, reverse bool) *Iterator {
+	if t.done {
+		return &Iterator{next: func() (entry, bool) { return entry{}, false }}
+	}
+	t.readRanges = append(t.readRanges, r)
+	next := t.writes.iterate(r, reverse, nil)
+	writes := func() (entry, bool) {
+		n := next()
+		if n == nil {
+			return entry{}, false
+		}
+		v := n.versions[0]
+		return entry{key: n.key, value: v.value, deleted: v.deleted}, true
+	}
+	return &Iterator{next: skipDeleted(merge(t.snap.entries(r, reverse), writes, reverse))}
+}
+
+// Commit commits the writes of t.
+//
+// It returns ErrConflict if a key which t read was written by another
+// transaction committed after the snapshot of t. In that case the writes
+// are discarded.
+func (t *Txn) Commit() error {
+	if t.done {
+		return ErrTxnDone
+	}
+	t.done = true
+	s := t.snap.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.active, t)
+	defer s.pruneLog()
+
+	var ws []write
+	for n := t.writes.head.next[0]; n != nil; n = n.next[0] {
+		v := n.versions[0]
+		ws = append(ws, write{key: n.key, value: v.value, deleted: v.deleted})
+	}
+	if len(ws) == 0 {
+		return nil
+	}
+	for _, c := range s.log {
+		if c.version > t.snap.version && t.conflicts(c.keys) {
+			return ErrConflict
+		}
+	}
+	s.commit(ws)
+	return nil
+}
+
+func (t *Txn) conflicts(keys [][]byte) bool {
+	for _, key := range keys {
+		if _, ok := t.reads[string(key)]; ok {
+			return true
+		}
+		for _, r := range t.readRanges {
+			if inRange(r, key) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Discard discards the writes of t. It does nothing if t is already
+// committed or discarded.
+func (t *Txn) Discard() {
+	if t.done {
+		return
+	}
+	t.done = true
+	s := t.snap.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.active, t)
+	s.pruneLog()
+}
+
+// Iterator iterates over the keys in a range.
+//
+//     for it := snap.Iterator(r); it.Next(); {
+//         key, value := it.Key(), it.Value()
+//     }
+type Iterator struct {
+	next  entryFunc
+	entry entry
+}
+
+// Next advances the iterator to the next key and reports whether there is
+// the key.
+func (it *Iterator) Next() bool {
+	e, ok := it.next()
+	if !ok {
+		it.entry = entry{}
+		return false
+	}
+	it.entry = e
+	return true
+}
+
+// Key returns the current key. The caller must not modify it.
+func (it *Iterator) Key() []byte {
+	return it.entry.key
+}
+
+// Value returns the value of the current key. The caller must not modify it.
+func (it *Iterator) Value() []byte {
+	return it.entry.value
+}
+
+type entry struct {
+	key     []byte
+	value   []byte
+	deleted bool
+}
+
+// entryFunc returns the entries one by one, and false after the last one.
+type entryFunc func() (entry, bool)
+
+func skipDeleted(next entryFunc) entryFunc {
+	return func() (entry, bool) {
+		for e, ok := next(); ok; e, ok = next() {
+			if !e.deleted {
+				return e, true
+			}
+		}
+		return entry{}, false
+	}
+}
+
+// merge merges the entries of base and overlay in the same order.
+// An entry of overlay hides the entry of base with the same key.
+func merge(base, overlay entryFunc, reverse bool) entryFunc {
+	b, bok := base()
+	o, ook := overlay()
+	return func() (entry, bool) {
+		var c int
+		switch {
+		case !bok && !ook:
+			return entry{}, false
+		case !ook:
+			c = -1
+		case !bok:
+			c = 1
+		default:
+			c = bytes.Compare(b.key, o.key)
+			if reverse {
+				c = -c
+			}
+		}
+		if c < 0 {
+			e := b
+			b, bok = base()
+			return e, true
+		}
+		e := o
+		if c == 0 {
+			b, bok = base()
+		}
+		o, ook = overlay()
+		return e, true
+	}
+}
@@ -0,0 +1,378 @@
+package memkv_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/hnakamur/sortedbytes"
+	"github.com/hnakamur/sortedbytes/memkv"
+)
+
+func key(user string, id int64) []byte {
+	return sortedbytes.AppendInt64(sortedbytes.AppendString(nil, user), id)
+}
+
+func userRange(t *testing.T, user string) sortedbytes.KeyRange {
+	t.Helper()
+	r, err := sortedbytes.PrefixRange(sortedbytes.AppendString(nil, user))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func collect(it *memkv.Iterator) []string {
+	var got []string
+	for it.Next() {
+		got = append(got, fmt.Sprintf("%x=%s", it.Key(), it.Value()))
+	}
+	return got
+}
+
+func entries(kvs ...interface{}) []string {
+	var want []string
+	for i := 0; i < len(kvs); i += 2 {
+		want = append(want, fmt.Sprintf("%x=%s", kvs[i], kvs[i+1]))
+	}
+	return want
+}
+
+func TestStore(t *testing.T) {
+	s := memkv.New()
+	for i := int64(-2); i <= 2; i++ {
+		s.Set(key("alice", i), []byte(fmt.Sprint(i)))
+	}
+	s.Set(key("bob", 0), []byte("b0"))
+	s.Set(key("alice", 1), []byte("one"))
+	s.Delete(key("alice", 0))
+	s.Delete(key("carol", 0))
+
+	if v, ok := s.Get(key("alice", 1)); !ok || string(v) != "one" {
+		t.Errorf("get unmatch: got=%q, %v, want=%q, true", v, ok, "one")
+	}
+	if v, ok := s.Get(key("alice", 0)); ok {
+		t.Errorf("get deleted key: got=%q, %v", v, ok)
+	}
+
+	r := userRange(t, "alice")
+	want := entries(key("alice", -2), "-2", key("alice", -1), "-1", key("alice", 1), "one", key("alice", 2), "2")
+	if got := collect(s.Snapshot().Iterator(r)); !reflect.DeepEqual(got, want) {
+		t.Errorf("iterator unmatch:\n got=%v\nwant=%v", got, want)
+	}
+	for i, j := 0, len(want)-1; i < j; i, j = i+1, j-1 {
+		want[i], want[j] = want[j], want[i]
+	}
+	if got := collect(s.Snapshot().ReverseIterator(r)); !reflect.DeepEqual(got, want) {
+		t.Errorf("reverse iterator unmatch:\n got=%v\nwant=%v", got, want)
+	}
+
+	comp := sortedbytes.ComponentRange(sortedbytes.AppendString(nil, "alice"),
+		sortedbytes.ExclusiveBound(sortedbytes.AppendInt64(nil, -2)),
+		sortedbytes.InclusiveBound(sortedbytes.AppendInt64(nil, 1)))
+	want = entries(key("alice", -1), "-1", key("alice", 1), "one")
+	if got := collect(s.Snapshot().Iterator(comp)); !reflect.DeepEqual(got, want) {
+		t.Errorf("iterator unmatch:\n got=%v\nwant=%v", got, want)
+	}
+	if got := collect(s.Snapshot().Iterator(sortedbytes.KeyRange{Begin: []byte("b"), End: []byte("a")})); got != nil {
+		t.Errorf("iterator of empty range: got=%v", got)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	s := memkv.New()
+	s.Set([]byte("a"), []byte("1"))
+	s.Set([]byte("b"), []byte("1"))
+	snap := s.Snapshot()
+	s.Set([]byte("a"), []byte("2"))
+	s.Delete([]byte("b"))
+	s.Set([]byte("c"), []byte("2"))
+
+	if v, ok := snap.Get([]byte("a")); !ok || string(v) != "1" {
+		t.Errorf("get unmatch: got=%q, %v, want=%q, true", v, ok, "1")
+	}
+	r := sortedbytes.KeyRange{Begin: []byte("a"), End: []byte("z")}
+	if got, want := collect(snap.Iterator(r)), entries("a", "1", "b", "1"); !reflect.DeepEqual(got, want) {
+		t.Errorf("iterator unmatch: got=%v, want=%v", got, want)
+	}
+	if got, want := collect(s.Snapshot().ReverseIterator(r)), entries("c", "2", "a", "2"); !reflect.DeepEqual(got, want) {
+		t.Errorf("iterator unmatch: got=%v, want=%v", got, want)
+	}
+
+	// A nil End means no upper bound.
+	all := sortedbytes.KeyRange{}
+	if got, want := collect(s.Snapshot().Iterator(all)), entries("a", "2", "c", "2"); !reflect.DeepEqual(got, want) {
+		t.Errorf("iterator of all keys unmatch: got=%v, want=%v", got, want)
+	}
+	if got, want := collect(s.Snapshot().ReverseIterator(all)), entries("c", "2", "a", "2"); !reflect.DeepEqual(got, want) {
+		t.Errorf("reverse iterator of all keys unmatch: got=%v, want=%v", got, want)
+	}
+	from := sortedbytes.KeyRange{Begin: []byte("b")}
+	if got, want := collect(s.Snapshot().ReverseIterator(from)), entries("c", "2"); !reflect.DeepEqual(got, want) {
+		t.Errorf("reverse iterator without end unmatch: got=%v, want=%v", got, want)
+	}
+	empty := sortedbytes.KeyRange{End: []byte{}}
+	if got := collect(s.Snapshot().Iterator(empty)); len(got) != 0 {
+		t.Errorf("iterator of empty range unmatch: got=%v, want=empty", got)
+	}
+}
+
+func TestTxn(t *testing.T) {
+	s := memkv.New()
+	s.Set([]byte("a"), []byte("1"))
+	s.Set([]byte("b"), []byte("1"))
+	s.Set([]byte("d"), []byte("1"))
+
+	txn := s.Begin()
+	if err := txn.Set([]byte("c"), []byte("2")); err != nil {
+		t.Fatal(err)
+	}
+	if err := txn.Set([]byte("a"), []byte("2")); err != nil {
+		t.Fatal(err)
+	}
+	if err := txn.Delete([]byte("b")); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok, err := txn.Get([]byte("c")); err != nil || !ok || string(v) != "2" {
+		t.Errorf("get unmatch: got=%q, %v, %v, want=%q, true", v, ok, err, "2")
+	}
+	if _, ok, err := txn.Get([]byte("b")); err != nil || ok {
+		t.Errorf("get deleted key: got=%v, %v", ok, err)
+	}
+	if _, ok := s.Get([]byte("c")); ok {
+		t.Errorf("uncommitted write is visible")
+	}
+
+	r := sortedbytes.KeyRange{Begin: []byte("a"), End: []byte("z")}
+	if got, want := collect(txn.Iterator(r)), entries("a", "2", "c", "2", "d", "1"); !reflect.DeepEqual(got, want) {
+		t.Errorf("iterator unmatch: got=%v, want=%v", got, want)
+	}
+	if got, want := collect(txn.ReverseIterator(r)), entries("d", "1", "c", "2", "a", "2"); !reflect.DeepEqual(got, want) {
+		t.Errorf("reverse iterator unmatch: got=%v, want=%v", got, want)
+	}
+	if got, want := collect(txn.Iterator(sortedbytes.KeyRange{})), entries("a", "2", "c", "2", "d", "1"); !reflect.DeepEqual(got, want) {
+		t.Errorf("iterator of all keys unmatch: got=%v, want=%v", got, want)
+	}
+	if got, want := collect(txn.ReverseIterator(sortedbytes.KeyRange{})), entries("d", "1", "c", "2", "a", "2"); !reflect.DeepEqual(got, want) {
+		t.Errorf("reverse iterator of all keys unmatch: got=%v, want=%v", got, want)
+	}
+
+	if err := txn.DeleteRange(sortedbytes.KeyRange{Begin: []byte("c"), End: []byte("e")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := txn.Set([]byte("cc"), []byte("3")); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	err := txn.Scan(r, func(key, value []byte) error {
+		got = append(got, fmt.Sprintf("%x=%s", key, value))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := entries("a", "2", "cc", "3"); !reflect.DeepEqual(got, want) {
+		t.Errorf("scan unmatch: got=%v, want=%v", got, want)
+	}
+
+	if err := txn.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := collect(s.Snapshot().Iterator(r)), entries("a", "2", "cc", "3"); !reflect.DeepEqual(got, want) {
+		t.Errorf("iterator unmatch: got=%v, want=%v", got, want)
+	}
+	if err := txn.Set([]byte("x"), nil); !errors.Is(err, memkv.ErrTxnDone) {
+		t.Errorf("error unmatch: got=%v, want=%v", err, memkv.ErrTxnDone)
+	}
+	if err := txn.Commit(); !errors.Is(err, memkv.ErrTxnDone) {
+		t.Errorf("error unmatch: got=%v, want=%v", err, memkv.ErrTxnDone)
+	}
+
+	discarded := s.Begin()
+	if err := discarded.Set([]byte("a"), []byte("4")); err != nil {
+		t.Fatal(err)
+	}
+	discarded.Discard()
+	if v, _ := s.Get([]byte("a")); string(v) != "2" {
+		t.Errorf("discarded write is visible: got=%q", v)
+	}
+
+	errStop := errors.New("stop")
+	txn = s.Begin()
+	defer txn.Discard()
+	if err := txn.Scan(r, func(key, value []byte) error { return errStop }); err != errStop {
+		t.Errorf("error unmatch: got=%v, want=%v", err, errStop)
+	}
+}
+
+func TestTxnConflict(t *testing.T) {
+	r := sortedbytes.KeyRange{Begin: []byte("a"), End: []byte("c")}
+	testCases := []struct {
+		name string
+		read func(txn *memkv.Txn) error
+		key  string
+		want error
+	}{
+		{
+			name: "get",
+			read: func(txn *memkv.Txn) error { _, _, err := txn.Get([]byte("a")); return err },
+			key:  "a",
+			want: memkv.ErrConflict,
+		},
+		{
+			name: "get other key",
+			read: func(txn *memkv.Txn) error { _, _, err := txn.Get([]byte("a")); return err },
+			key:  "b",
+			want: nil,
+		},
+		{
+			name: "get missing key",
+			read: func(txn *memkv.Txn) error { _, _, err := txn.Get([]byte("b")); return err },
+			key:  "b",
+			want: memkv.ErrConflict,
+		},
+		{
+			name: "scan",
+			read: func(txn *memkv.Txn) error { return txn.Scan(r, func(_, _ []byte) error { return nil }) },
+			key:  "b",
+			want: memkv.ErrConflict,
+		},
+		{
+			name: "scan other range",
+			read: func(txn *memkv.Txn) error { return txn.Scan(r, func(_, _ []byte) error { return nil }) },
+			key:  "c",
+			want: nil,
+		},
+		{
+			name: "scan all",
+			read: func(txn *memkv.Txn) error {
+				return txn.Scan(sortedbytes.KeyRange{}, func(_, _ []byte) error { return nil })
+			},
+			key:  "y",
+			want: memkv.ErrConflict,
+		},
+		{
+			name: "delete range",
+			read: func(txn *memkv.Txn) error { return txn.DeleteRange(r) },
+			key:  "b",
+			want: memkv.ErrConflict,
+		},
+		{
+			name: "blind write",
+			read: func(txn *memkv.Txn) error { return nil },
+			key:  "a",
+			want: nil,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := memkv.New()
+			s.Set([]byte("a"), []byte("1"))
+			txn := s.Begin()
+			if err := tc.read(txn); err != nil {
+				t.Fatal(err)
+			}
+			if err := txn.Set([]byte("z"), []byte("2")); err != nil {
+				t.Fatal(err)
+			}
+
+			other := s.Begin()
+			if err := other.Set([]byte(tc.key), []byte("3")); err != nil {
+				t.Fatal(err)
+			}
+			if err := other.Commit(); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := txn.Commit(); err != tc.want {
+				t.Errorf("error unmatch: got=%v, want=%v", err, tc.want)
+			}
+			if _, ok := s.Get([]byte("z")); ok != (tc.want == nil) {
+				t.Errorf("write of txn visible: got=%v, want=%v", ok, tc.want == nil)
+			}
+		})
+	}
+
+	t.Run("read only", func(t *testing.T) {
+		s := memkv.New()
+		txn := s.Begin()
+		if _, _, err := txn.Get([]byte("a")); err != nil {
+			t.Fatal(err)
+		}
+		s.Set([]byte("a"), []byte("1"))
+		if err := txn.Commit(); err != nil {
+			t.Errorf("got error: %v", err)
+		}
+	})
+	t.Run("committed before begin", func(t *testing.T) {
+		s := memkv.New()
+		old := s.Begin()
+		defer old.Discard()
+		other := s.Begin()
+		if err := other.Set([]byte("a"), []byte("1")); err != nil {
+			t.Fatal(err)
+		}
+		if err := other.Commit(); err != nil {
+			t.Fatal(err)
+		}
+		txn := s.Begin()
+		if _, _, err := txn.Get([]byte("a")); err != nil {
+			t.Fatal(err)
+		}
+		if err := txn.Set([]byte("a"), []byte("2")); err != nil {
+			t.Fatal(err)
+		}
+		if err := txn.Commit(); err != nil {
+			t.Errorf("got error: %v", err)
+		}
+	})
+}
+
+func TestConcurrent(t *testing.T) {
+	s := memkv.New()
+	r := sortedbytes.KeyRange{Begin: []byte{0}, End: []byte{0xff}}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				k := key(fmt.Sprint(i), int64(j))
+				for {
+					txn := s.Begin()
+					n := 0
+					if err := txn.Scan(r, func(_, _ []byte) error { n++; return nil }); err != nil {
+						t.Error(err)
+						return
+					}
+					if err := txn.Set(k, []byte(fmt.Sprint(n))); err != nil {
+						t.Error(err)
+						return
+					}
+					err := txn.Commit()
+					if err == nil {
+						break
+					}
+					if err != memkv.ErrConflict {
+						t.Error(err)
+						return
+					}
+				}
+				collect(s.Snapshot().ReverseIterator(r))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// Each transaction counted the keys written before it, so the counts
+	// are all distinct from 0 to 399.
+	seen := make(map[string]bool)
+	for it := s.Snapshot().Iterator(r); it.Next(); {
+		seen[string(it.Value())] = true
+	}
+	if got, want := len(seen), 400; got != want {
+		t.Errorf("distinct count unmatch: got=%d, want=%d", got, want)
+	}
+}
@@ -0,0 +1,179 @@
+package memkv
+
+import (
+	"bytes"
+	"math/rand"
+	"sync"
+
+	"github.com/hnakamur/sortedbytes"
+)
+
+const (
+	maxLevel = 16
+	// One in levelRatio nodes at a level is also at the next level.
+	levelRatio = 4
+)
+
+// node is a node of a skiplist. Nodes are never removed, and a deleted
+// key is a node whose latest version is a tombstone.
+type node struct {
+	key      []byte
+	versions []version // in the ascending order of version
+	next     []*node
+}
+
+type version struct {
+	version uint64
+	value   []byte
+	deleted bool
+}
+
+// at returns the latest version which is not newer than v.
+func (n *node) at(v uint64) (ver version, ok bool) {
+	for i := len(n.versions) - 1; i >= 0; i-- {
+		if n.versions[i].version <= v {
+			return n.versions[i], true
+		}
+	}
+	return version{}, false
+}
+
+// skiplist is a sorted map from keys to nodes.
+//
+// skiplist is not safe for concurrent use. The Store guards its skiplist
+// with its mutex.
+type skiplist struct {
+	head  node
+	level int
+	rnd   *rand.Rand
+}
+
+func newSkiplist() *skiplist {
+	return &skiplist{
+		head:  node{next: make([]*node, maxLevel)},
+		level: 1,
+		rnd:   rand.New(rand.NewSource(1)),
+	}
+}
+
+// findGE returns the first node whose key is greater than or equal to key,
+// or nil if there is no such node. If prev is not nil, it is filled with
+// the last node before the result at each level.
+func (l *skiplist) findGE(key []byte, prev []*node) *node {
+	x := &l.head
+	for i := l.level - 1; i >= 0; i-- {
+		for next := x.next[i]; next != nil && bytes.Compare(next.key, key) < 0; next = x.next[i] {
+			x = next
+		}
+		if prev != nil {
+			prev[i] = x
+		}
+	}
+	return x.next[0]
+}
+
+// findLT returns the last node whose key is less than key, or nil if there
+// is no such node.
+func (l *skiplist) findLT(key []byte) *node {
+	x := &l.head
+	for i := l.level - 1; i >= 0; i-- {
+		for next := x.next[i]; next != nil && bytes.Compare(next.key, key) < 0; next = x.next[i] {
+			x = next
+		}
+	}
+	if x == &l.head {
+		return nil
+	}
+	return x
+}
+
+// last returns the last node, or nil if l is empty.
+func (l *skiplist) last() *node {
+	x := &l.head
+	for i := l.level - 1; i >= 0; i-- {
+		for x.next[i] != nil {
+			x = x.next[i]
+		}
+	}
+	if x == &l.head {
+		return nil
+	}
+	return x
+}
+
+// getOrInsert returns the node of key, and inserts a new node if there is
+// no node of key. The key of a new node is a copy of key.
+func (l *skiplist) getOrInsert(key []byte) *node {
+	var prev [maxLevel]*node
+	if n := l.findGE(key, prev[:]); n != nil && bytes.Equal(n.key, key) {
+		return n
+	}
+
+	level := 1
+	for level < maxLevel && l.rnd.Intn(levelRatio) == 0 {
+		level++
+	}
+	if level > l.level {
+		for i := l.level; i < level; i++ {
+			prev[i] = &l.head
+		}
+		l.level = level
+	}
+	n := &node{key: append([]byte(nil), key...), next: make([]*node, level)}
+	for i := 0; i < level; i++ {
+		n.next[i] = prev[i].next[i]
+		prev[i].next[i] = n
+	}
+	return n
+}
+
+// get returns the node of key, or nil if there is no node of key.
+func (l *skiplist) get(key []byte) *node {
+	if n := l.findGE(key, nil); n != nil && bytes.Equal(n.key, key) {
+		return n
+	}
+	return nil
+}
+
+// iterate returns a function which returns the nodes in r one by one in
+// the ascending order of keys, or in the descending order if reverse is
+// true. A nil End of r means no upper bound. The returned function locks lk
+// during each call if lk is not nil.
+func (l *skiplist) iterate(r sortedbytes.KeyRange, reverse bool, lk sync.Locker) func() *node {
+	var n *node
+	started := false
+	return func() *node {
+		if lk != nil {
+			lk.Lock()
+			defer lk.Unlock()
+		}
+		switch {
+		case !started && !reverse:
+			n = l.findGE(r.Begin, nil)
+		case !started && reverse && r.End == nil:
+			n = l.last()
+		case !started && reverse:
+			n = l.findLT(r.End)
+		case n == nil:
+			return nil
+		case !reverse:
+			n = n.next[0]
+		default:
+			n = l.findLT(n.key)
+		}
+		started = true
+		if n == nil || !inRange(r, n.key) {
+			n = nil
+		}
+		return n
+	}
+}
+
+// inRange reports whether key is in r, where a nil End of r means no upper
+// bound.
+func inRange(r sortedbytes.KeyRange, key []byte) bool {
+	if r.End == nil {
+		return bytes.Compare(r.Begin, key) <= 0
+	}
+	return r.Contains(key)
+}
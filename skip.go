@@ -0,0 +1,175 @@
+package sortedbytes
+
+import (
+	"bytes"
+	"io"
+)
+
+// Skip skips an encoded value at the start of b without decoding it and
+// returns the rest of b.
+//
+// Skip accepts the values encoded by any Append function including
+// the Desc counterparts, and a nested tuple encoded by AppendNested.
+// It does not allocate memory unless it returns an error.
+func Skip(b []byte) (rest []byte, err error) {
+	n, err := encodedLen(b)
+	if err != nil {
+		return b, newDecodeError(b, err, nil)
+	}
+	return b[n:], nil
+}
+
+// SkipN skips n encoded values at the start of b without decoding them and
+// returns the rest of b.
+//
+// If SkipN fails, the returned error is a *DecodeError whose Offset is
+// the offset in b.
+func SkipN(b []byte, n int) (rest []byte, err error) {
+	rest = b
+	for i := 0; i < n; i++ {
+		if rest, err = Skip(rest); err != nil {
+			return b, AddOffset(err, len(b)-len(rest))
+		}
+	}
+	return rest, nil
+}
+
+// encodedLen returns the length of the encoded value at the start of b.
+func encodedLen(b []byte) (int, error) {
+	if len(b) == 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	c := b[0]
+	switch c {
+	case typeCodeNull:
+		return 1, nil
+	case typeCodeByteString, typeCodeUTF8String:
+		n, err := escapedLen(b[1:])
+		return 1 + n, err
+	case ^byte(typeCodeByteString), ^byte(typeCodeUTF8String):
+		n, err := descEscapedLen(b[1:])
+		return 1 + n, err
+	case typeCodeNested:
+		n, err := nestedLen(b[1:])
+		return 1 + n, err
+	case typeCodePositiveBigInt, ^byte(typeCodeNegativeBigInt):
+		if len(b) < 2 {
+			return 0, io.ErrUnexpectedEOF
+		}
+		return checkLen(b, 2+int(b[1]))
+	case typeCodeNegativeBigInt, ^byte(typeCodePositiveBigInt):
+		if len(b) < 2 {
+			return 0, io.ErrUnexpectedEOF
+		}
+		return checkLen(b, 2+int(^b[1]))
+	}
+
+	if c > typeCodeTime {
+		c = ^c
+		if c == typeCodeVersionstamp {
+			// The descending order encoding of a versionstamp is not defined.
+			return 0, ErrUnexpectedTypeCode
+		}
+	}
+	n, ok := fixedPayloadLen(c)
+	if !ok {
+		return 0, ErrUnexpectedTypeCode
+	}
+	return checkLen(b, 1+n)
+}
+
+func checkLen(b []byte, n int) (int, error) {
+	if len(b) < n {
+		return 0, io.ErrUnexpectedEOF
+	}
+	return n, nil
+}
+
+// fixedPayloadLen returns the length of the payload which follows
+// the type code c in the ascending order if the length is fixed for c.
+func fixedPayloadLen(c byte) (n int, ok bool) {
+	switch {
+	case typeCodeNegativeInt64 <= c && c <= typeCodePositiveInt64:
+		return intPayloadLen(c), true
+	case c == typeCodeFloat32:
+		return 4, true
+	case c == typeCodeFloat64:
+		return 8, true
+	case c == typeCodeFalse || c == typeCodeTrue:
+		return 0, true
+	case c == typeCodeUUID:
+		return 16, true
+	case c == typeCodeVersionstamp:
+		return 12, true
+	case c == typeCodeTime:
+		return 12, true
+	default:
+		return 0, false
+	}
+}
+
+// escapedLen returns the length of the value escaped by AppendString or
+// AppendBytes at the start of src including the terminator.
+func escapedLen(src []byte) (int, error) {
+	n := 0
+	for {
+		i := bytes.IndexByte(src[n:], '\x00')
+		if i == -1 {
+			return 0, io.ErrUnexpectedEOF
+		}
+		n += i + 1
+		if n < len(src) && src[n] == '\xFF' {
+			n++
+			continue
+		}
+		return n, nil
+	}
+}
+
+// descEscapedLen returns the length of the value escaped by AppendStringDesc
+// or AppendBytesDesc at the start of src including the terminator.
+func descEscapedLen(src []byte) (int, error) {
+	n := 0
+	for {
+		i := bytes.IndexByte(src[n:], '\xFF')
+		if i == -1 || n+i+1 >= len(src) {
+			return 0, io.ErrUnexpectedEOF
+		}
+		n += i + 2
+		switch src[n-1] {
+		case '\x00':
+			continue
+		case '\xFF':
+			return n, nil
+		default:
+			return 0, ErrInvalidEscape
+		}
+	}
+}
+
+// nestedLen returns the length of the elements of the nested tuple
+// encoded by AppendNested at the start of src including the terminator.
+func nestedLen(src []byte) (int, error) {
+	n := 0
+	for {
+		if n >= len(src) {
+			return 0, io.ErrUnexpectedEOF
+		}
+		if src[n] == typeCodeNull {
+			if n+1 < len(src) && src[n+1] == '\xFF' {
+				n += 2
+				continue
+			}
+			return n + 1, nil
+		}
+		if src[n] > typeCodeTime {
+			// Nested tuples contain only ascending order values.
+			return 0, ErrUnexpectedTypeCode
+		}
+		m, err := encodedLen(src[n:])
+		if err != nil {
+			return 0, err
+		}
+		n += m
+	}
+}
@@ -0,0 +1,134 @@
+package sortedbytes_test
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/hnakamur/sortedbytes"
+)
+
+func skipTestValues() [][]byte {
+	now := time.Date(2020, 5, 17, 12, 34, 56, 789, time.UTC)
+	bigPos := mustParseBigInt("0x10000000000000000")
+	bigNeg := new(big.Int).Neg(bigPos)
+	return [][]byte{
+		sortedbytes.AppendNullString(nil, sql.NullString{}),
+		sortedbytes.AppendString(nil, "foo\x00bar"),
+		sortedbytes.AppendBytes(nil, []byte("\x00\x00")),
+		sortedbytes.AppendInt32(nil, -1),
+		sortedbytes.AppendInt64(nil, math.MaxInt64),
+		sortedbytes.AppendUint32(nil, 0),
+		sortedbytes.AppendVarInt(nil, -0x1234),
+		sortedbytes.AppendBigInt(nil, bigPos),
+		sortedbytes.AppendBigInt(nil, bigNeg),
+		sortedbytes.AppendFloat32(nil, 1.5),
+		sortedbytes.AppendFloat64(nil, -1.5),
+		sortedbytes.AppendBool(nil, true),
+		sortedbytes.AppendUUID(nil, sortedbytes.UUID{1}),
+		sortedbytes.AppendVersionstamp(nil, sortedbytes.IncompleteVersionstamp(1)),
+		sortedbytes.AppendTime(nil, now),
+		sortedbytes.AppendNested(nil, sortedbytes.Tuple{nil, "a", sortedbytes.Tuple{nil, 1}, 2}),
+		sortedbytes.AppendStringDesc(nil, "foo\x00bar"),
+		sortedbytes.AppendBytesDesc(nil, []byte("\xff\x00")),
+		sortedbytes.AppendNullInt32Desc(nil, sql.NullInt32{}),
+		sortedbytes.AppendInt32Desc(nil, -1),
+		sortedbytes.AppendInt64Desc(nil, math.MinInt64),
+		sortedbytes.AppendVarIntDesc(nil, 0x1234),
+		sortedbytes.AppendBigIntDesc(nil, bigPos),
+		sortedbytes.AppendBigIntDesc(nil, bigNeg),
+		sortedbytes.AppendFloat32Desc(nil, 1.5),
+		sortedbytes.AppendFloat64Desc(nil, -1.5),
+		sortedbytes.AppendBoolDesc(nil, false),
+		sortedbytes.AppendUUIDDesc(nil, sortedbytes.UUID{1}),
+		sortedbytes.AppendTimeDesc(nil, now),
+	}
+}
+
+func TestSkip(t *testing.T) {
+	t.Run("each", func(t *testing.T) {
+		for i, v := range skipTestValues() {
+			b := append(append([]byte(nil), v...), 0x14)
+			rest, err := sortedbytes.Skip(b)
+			if err != nil {
+				t.Errorf("case %d: got error: %s, b=0x%x", i, err, b)
+				continue
+			}
+			if want := []byte{0x14}; !bytes.Equal(rest, want) {
+				t.Errorf("case %d: rest unmatch: got=0x%x, want=0x%x", i, rest, want)
+			}
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		testCases := [][]byte{
+			nil,
+			[]byte("\x02foo"),
+			[]byte("\xfdfoo\xff"),
+			[]byte("\xfdfoo\xff\x01"),
+			[]byte("\x05\x02a\x00"),
+			[]byte("\x05\xeb\x00"),
+			[]byte("\x1d"),
+			[]byte("\x1d\x02\x01"),
+			[]byte("\x0b\xfd\x01"),
+			[]byte("\x19\x00\x00\x00"),
+			[]byte("\x03"),
+			[]byte("\xcc"),
+		}
+		for i, input := range testCases {
+			rest, err := sortedbytes.Skip(input)
+			if err == nil {
+				t.Errorf("case %d: got no error", i)
+			}
+			if !bytes.Equal(rest, input) {
+				t.Errorf("case %d: rest unmatch: got=0x%x, want=0x%x", i, rest, input)
+			}
+		}
+	})
+}
+
+func TestSkipN(t *testing.T) {
+	values := skipTestValues()
+	var b []byte
+	for _, v := range values {
+		b = append(b, v...)
+	}
+	t.Run("offset", func(t *testing.T) {
+		offset := 0
+		for n := 0; n <= len(values); n++ {
+			rest, err := sortedbytes.SkipN(b, n)
+			if err != nil {
+				t.Fatalf("n=%d: got error: %s", n, err)
+			}
+			if got, want := len(b)-len(rest), offset; got != want {
+				t.Errorf("n=%d: skipped length unmatch: got=%d, want=%d", n, got, want)
+			}
+			if n < len(values) {
+				offset += len(values[n])
+			}
+		}
+	})
+	t.Run("allocs", func(t *testing.T) {
+		allocs := testing.AllocsPerRun(10, func() {
+			if _, err := sortedbytes.SkipN(b, len(values)); err != nil {
+				t.Fatal(err)
+			}
+		})
+		if allocs != 0 {
+			t.Errorf("allocs unmatch: got=%v, want=0", allocs)
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, err := sortedbytes.SkipN(b, len(values)+1)
+		var de *sortedbytes.DecodeError
+		if !errors.As(err, &de) {
+			t.Fatalf("got error %v, want *DecodeError", err)
+		}
+		if got, want := de.Offset, len(b); got != want {
+			t.Errorf("offset unmatch: got=%d, want=%d", got, want)
+		}
+	})
+}
@@ -4,44 +4,85 @@
 // which is capable to do range scans.
 //
 // Supported types are
-// bool, int32, int64, float64, string,
-// sql.NulBool, sql.NullInt32, sql.NullInt64, sql.NullFloat64, and sql.NullString.
+// bool, int32, int64, uint32, uint64, *big.Int, float32, float64, string,
+// []byte, time.Time, UUID, sql.NulBool, sql.NullInt32, sql.NullInt64,
+// sql.NullFloat64, sql.NullString, sql.NullTime, NullUint32, NullUint64,
+// NullFloat32, NullBytes, and NullUUID.
 //
-// Note time.Time and sql.NullTime are not supported.
-// You can use int64 or sql.NullInt64 for timestamps with time.Time.Unix() or
-// time.Time.UnixNano().
+// Note time.Time and sql.NullTime values are encoded as instants and
+// decoded in UTC, so their locations are not preserved.
+//
+// Each Append function has a Desc counterpart like AppendInt64Desc, which
+// encodes a value in the descending order, and it must be decoded with the
+// Take function of the same counterpart like TakeInt64Desc. You can mix
+// the ascending and descending order components in a composite key.
+//
+// Tuple packs and unpacks a list of values of heterogeneous types, so that
+// you can decode a key without knowing its schema in advance.
+// Tuple.PackWithVersionstamp also reports the offset of the placeholder
+// of an incomplete Versionstamp, which a storage engine fills in with
+// the commit version.
+//
+// Take functions return a *DecodeError on failure, which has the offset
+// and the type code of the value and wraps one of the sentinel errors like
+// ErrUnexpectedTypeCode and io.ErrUnexpectedEOF.
+//
+// PeekKind reports the kind of the next encoded value and Skip skips it,
+// so that generic tools can walk over a key without knowing its schema.
+//
+// Validate and the strict Take functions like TakeInt64Strict reject
+// encodings which the Append functions never produce, so that each key
+// has only one encoded form.
+//
+// PrefixRange and ComponentRange return the KeyRange to scan the keys
+// which start with some components, and Subspace packs and unpacks keys
+// under a common prefix like the subspace of the FDB tuple layer.
+//
+// Marshal and Unmarshal encode and decode a struct as a composite key
+// according to the "sortedbytes" field tags.
+//
+// The sortedbytes-gen command generates AppendKey and TakeKey methods
+// for a struct type, which do the same without reflection.
 package sortedbytes
 
 // The encoding in this package is a subset of the FDB Tuple layer typecodes encoding.
 // https://github.com/apple/foundationdb/blob/92b41e3562e639e16dbe0142cc479a3304e9c08a/design/tuple.md
 // https://activesphere.com/blog/2018/08/17/order-preserving-serialization
+//
+// One difference from the FDB Tuple layer is that int32 values are encoded
+// with the type codes 0x0F and 0x19 followed by 4 bytes while these type
+// codes are followed by 5 bytes in the FDB Tuple layer.
 
 import (
 	"bytes"
 	"database/sql"
 	"encoding/binary"
-	"errors"
 	"io"
 	"math"
 	"strings"
 )
 
 const (
-	typeCodeNull          = 0x00
-	typeCodeUTF8String    = 0x02
-	typeCodeNegativeInt64 = 0x0C
-	typeCodeNegativeInt32 = 0x0F
-	typeCodeIntZero       = 0x14
-	typeCodePositiveInt32 = 0x19
-	typeCodePositiveInt64 = 0x1C
-	typeCodeFloat64       = 0x21
-	typeCodeFalse         = 0x26
-	typeCodeTrue          = 0x27
+	typeCodeNull           = 0x00
+	typeCodeByteString     = 0x01
+	typeCodeUTF8String     = 0x02
+	typeCodeNested         = 0x05
+	typeCodeNegativeBigInt = 0x0B
+	typeCodeNegativeInt64  = 0x0C
+	typeCodeNegativeInt32  = 0x0F
+	typeCodeIntZero        = 0x14
+	typeCodePositiveInt32  = 0x19
+	typeCodePositiveInt64  = 0x1C
+	typeCodePositiveBigInt = 0x1D
+	typeCodeFloat32        = 0x20
+	typeCodeFloat64        = 0x21
+	typeCodeFalse          = 0x26
+	typeCodeTrue           = 0x27
+	typeCodeUUID           = 0x30
+	typeCodeVersionstamp   = 0x33
+	typeCodeTime           = 0x40
 )
 
-var errUnpexptedTypeCode = errors.New("unexpected type code")
-var errValueOutOfRange = errors.New("value out of range")
-
 // AppendNullString appends a sql.NullString value to dst.
 //
 // You need to store the result of AppendNullString like:
@@ -75,7 +116,7 @@ func TakeNullString(b []byte) (value sql.NullString, rest []byte, err error) {
 	var c byte
 	c, rest, err = takeTypeCode(b)
 	if err != nil {
-		return value, b, err
+		return value, b, newNullDecodeError(b, err, expectedString)
 	}
 	switch c {
 	case typeCodeNull:
@@ -83,11 +124,11 @@ func TakeNullString(b []byte) (value sql.NullString, rest []byte, err error) {
 	case typeCodeUTF8String:
 		s, rest, err := takeStringValue(rest)
 		if err != nil {
-			return value, b, err
+			return value, b, newNullDecodeError(b, err, expectedString)
 		}
 		return sql.NullString{Valid: true, String: s}, rest, nil
 	default:
-		return value, b, errUnpexptedTypeCode
+		return value, b, newNullDecodeError(b, ErrUnexpectedTypeCode, expectedString)
 	}
 }
 
@@ -95,37 +136,153 @@ func TakeNullString(b []byte) (value sql.NullString, rest []byte, err error) {
 func TakeString(b []byte) (value string, rest []byte, err error) {
 	rest, err = expectTypeCode(b, typeCodeUTF8String)
 	if err != nil {
-		return "", b, err
+		return "", b, newDecodeError(b, err, expectedString)
 	}
 	value, rest, err = takeStringValue(rest)
 	if err != nil {
-		return "", b, err
+		return "", b, newDecodeError(b, err, expectedString)
+	}
+	return value, rest, nil
+}
+
+// TakeStringBytes takes a string value from b and returns it as a []byte
+// and the rest of b.
+//
+// The returned value is a sub-slice of b when the value contains no 0x00
+// bytes, so TakeStringBytes does not allocate memory in that case.
+// Otherwise the value is unescaped into buf[:0], which is grown if needed.
+// In either case the returned value is only valid until b or buf is
+// modified.
+func TakeStringBytes(b []byte, buf []byte) (value []byte, rest []byte, err error) {
+	rest, err = expectTypeCode(b, typeCodeUTF8String)
+	if err != nil {
+		return nil, b, newDecodeError(b, err, expectedString)
+	}
+	value, rest, err = takeEscapedValue(rest, buf)
+	if err != nil {
+		return nil, b, newDecodeError(b, err, expectedString)
 	}
 	return value, rest, nil
 }
 
 func takeStringValue(src []byte) (value string, rest []byte, err error) {
-	var out []byte
+	var v []byte
+	v, rest, err = takeEscapedValue(src, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	return string(v), rest, nil
+}
+
+// takeEscapedValue takes a value escaped by AppendString or AppendBytes
+// from src and returns it and the rest of src.
+// The returned value is a sub-slice of src when the value contains no
+// escaped bytes. Otherwise it is unescaped and appended to buf[:0].
+func takeEscapedValue(src, buf []byte) (value []byte, rest []byte, err error) {
+	out := buf[:0]
+	escaped := false
 	for {
 		i := bytes.IndexByte(src, '\x00')
 		if i == -1 {
-			return "", nil, io.ErrUnexpectedEOF
+			return nil, nil, io.ErrUnexpectedEOF
 		}
 
 		if i+1 < len(src) && src[i+1] == '\xFF' {
-			if out == nil {
-				out = []byte{}
-			}
 			out = append(out, src[:i+1]...)
 			src = src[i+2:]
+			escaped = true
 			continue
 		}
 
-		if out == nil {
-			return string(src[:i]), src[i+1:], nil
+		if !escaped {
+			return src[:i], src[i+1:], nil
+		}
+		return append(out, src[:i]...), src[i+1:], nil
+	}
+}
+
+// NullBytes represents a []byte that may be null.
+type NullBytes struct {
+	Bytes []byte
+	Valid bool // Valid is true if Bytes is not NULL
+}
+
+// AppendNullBytes appends a NullBytes value to dst.
+//
+// You need to store the result of AppendNullBytes like:
+//     dst = sortedbytes.AppendNullBytes(dst, value)
+func AppendNullBytes(dst []byte, value NullBytes) []byte {
+	if value.Valid {
+		return AppendBytes(dst, value.Bytes)
+	}
+	return append(dst, typeCodeNull)
+}
+
+// AppendBytes appends a byte string value to dst.
+//
+// The value is escaped in the same way as AppendString, but the type code
+// is the FDB byte string type code 0x01, so all byte strings sort before
+// all strings.
+//
+// You need to store the result of AppendBytes like:
+//     dst = sortedbytes.AppendBytes(dst, value)
+func AppendBytes(dst []byte, value []byte) []byte {
+	dst = append(dst, typeCodeByteString)
+	for {
+		i := bytes.IndexByte(value, '\x00')
+		if i == -1 {
+			return append(append(dst, value...), '\x00')
+		}
+
+		dst = append(append(dst, value[:i+1]...), '\xFF')
+		value = value[i+1:]
+	}
+}
+
+// TakeNullBytes takes a NullBytes value from b and returns it and the rest of b.
+func TakeNullBytes(b []byte) (value NullBytes, rest []byte, err error) {
+	var c byte
+	c, rest, err = takeTypeCode(b)
+	if err != nil {
+		return value, b, newNullDecodeError(b, err, expectedBytes)
+	}
+	switch c {
+	case typeCodeNull:
+		return value, b[1:], nil
+	case typeCodeByteString:
+		v, rest, err := takeBytesValue(rest)
+		if err != nil {
+			return value, b, newNullDecodeError(b, err, expectedBytes)
 		}
-		return string(append(out, src[:i]...)), src[i+1:], nil
+		return NullBytes{Valid: true, Bytes: v}, rest, nil
+	default:
+		return value, b, newNullDecodeError(b, ErrUnexpectedTypeCode, expectedBytes)
+	}
+}
+
+// TakeBytes takes a byte string value from b and returns it and the rest of b.
+// The returned value does not share the underlying array with b.
+func TakeBytes(b []byte) (value []byte, rest []byte, err error) {
+	rest, err = expectTypeCode(b, typeCodeByteString)
+	if err != nil {
+		return nil, b, newDecodeError(b, err, expectedBytes)
 	}
+	value, rest, err = takeBytesValue(rest)
+	if err != nil {
+		return nil, b, newDecodeError(b, err, expectedBytes)
+	}
+	return value, rest, nil
+}
+
+func takeBytesValue(src []byte) (value []byte, rest []byte, err error) {
+	var v []byte
+	v, rest, err = takeEscapedValue(src, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	value = make([]byte, len(v))
+	copy(value, v)
+	return value, rest, nil
 }
 
 // AppendNullInt32 appends a NullInt32 value to dst.
@@ -163,7 +320,7 @@ func TakeNullInt32(b []byte) (value sql.NullInt32, rest []byte, err error) {
 	var c byte
 	c, rest, err = takeTypeCode(b)
 	if err != nil {
-		return value, b, err
+		return value, b, newNullDecodeError(b, err, expectedInt32)
 	}
 	if c == typeCodeNull {
 		return value, b[1:], nil
@@ -171,7 +328,7 @@ func TakeNullInt32(b []byte) (value sql.NullInt32, rest []byte, err error) {
 	var v int32
 	v, rest, err = takeInt32Value(c, rest)
 	if err != nil {
-		return value, b, err
+		return value, b, newNullDecodeError(b, err, expectedInt32)
 	}
 	return sql.NullInt32{Valid: true, Int32: v}, rest, nil
 }
@@ -181,11 +338,11 @@ func TakeInt32(b []byte) (value int32, rest []byte, err error) {
 	var c byte
 	c, rest, err = takeTypeCode(b)
 	if err != nil {
-		return 0, b, err
+		return 0, b, newDecodeError(b, err, expectedInt32)
 	}
 	value, rest, err = takeInt32Value(c, rest)
 	if err != nil {
-		return 0, b, err
+		return 0, b, newDecodeError(b, err, expectedInt32)
 	}
 	return value, rest, nil
 }
@@ -200,7 +357,7 @@ func takeInt32Value(c byte, b []byte) (value int32, rest []byte, err error) {
 		}
 		v := binary.BigEndian.Uint32(b[:4])
 		if v > math.MaxInt32 {
-			return 0, nil, errValueOutOfRange
+			return 0, nil, ErrValueOutOfRange
 		}
 		return int32(v), b[4:], nil
 	case typeCodeNegativeInt32:
@@ -209,11 +366,11 @@ func takeInt32Value(c byte, b []byte) (value int32, rest []byte, err error) {
 		}
 		v := math.MaxUint32 - binary.BigEndian.Uint32(b[:4])
 		if v > -math.MinInt32 {
-			return 0, nil, errValueOutOfRange
+			return 0, nil, ErrValueOutOfRange
 		}
 		return -int32(v), b[4:], nil
 	default:
-		return value, nil, errUnpexptedTypeCode
+		return value, nil, ErrUnexpectedTypeCode
 	}
 }
 
@@ -252,7 +409,7 @@ func TakeNullInt64(b []byte) (value sql.NullInt64, rest []byte, err error) {
 	var c byte
 	c, rest, err = takeTypeCode(b)
 	if err != nil {
-		return value, b, err
+		return value, b, newNullDecodeError(b, err, expectedInt64)
 	}
 	if c == typeCodeNull {
 		return value, b[1:], nil
@@ -260,7 +417,7 @@ func TakeNullInt64(b []byte) (value sql.NullInt64, rest []byte, err error) {
 	var v int64
 	v, rest, err = takeInt64Value(c, rest)
 	if err != nil {
-		return value, b, err
+		return value, b, newNullDecodeError(b, err, expectedInt64)
 	}
 	return sql.NullInt64{Valid: true, Int64: v}, rest, nil
 }
@@ -270,11 +427,11 @@ func TakeInt64(b []byte) (value int64, rest []byte, err error) {
 	var c byte
 	c, rest, err = takeTypeCode(b)
 	if err != nil {
-		return 0, b, err
+		return 0, b, newDecodeError(b, err, expectedInt64)
 	}
 	value, rest, err = takeInt64Value(c, rest)
 	if err != nil {
-		return 0, b, err
+		return 0, b, newDecodeError(b, err, expectedInt64)
 	}
 	return value, rest, nil
 }
@@ -289,7 +446,7 @@ func takeInt64Value(c byte, b []byte) (value int64, rest []byte, err error) {
 		}
 		v := binary.BigEndian.Uint64(b[:8])
 		if v > math.MaxInt64 {
-			return 0, nil, errValueOutOfRange
+			return 0, nil, ErrValueOutOfRange
 		}
 		return int64(v), b[8:], nil
 	case typeCodeNegativeInt64:
@@ -298,14 +455,268 @@ func takeInt64Value(c byte, b []byte) (value int64, rest []byte, err error) {
 		}
 		v := math.MaxUint64 - binary.BigEndian.Uint64(b[:8])
 		if v > -math.MinInt64 {
-			return 0, nil, errValueOutOfRange
+			return 0, nil, ErrValueOutOfRange
 		}
 		return -int64(v), b[8:], nil
 	default:
-		return value, nil, errUnpexptedTypeCode
+		return value, nil, ErrUnexpectedTypeCode
+	}
+}
+
+// NullUint32 represents an uint32 that may be null.
+// It is the unsigned counterpart of sql.NullInt32.
+type NullUint32 struct {
+	Uint32 uint32
+	Valid  bool // Valid is true if Uint32 is not NULL
+}
+
+// AppendNullUint32 appends a NullUint32 value to dst.
+//
+// You need to store the result of AppendNullUint32 like:
+//     dst = sortedbytes.AppendNullUint32(dst, value)
+func AppendNullUint32(dst []byte, value NullUint32) []byte {
+	if value.Valid {
+		return AppendUint32(dst, value.Uint32)
+	}
+	return append(dst, typeCodeNull)
+}
+
+// AppendUint32 appends an uint32 value to dst.
+//
+// The encoding uses the same type codes as AppendInt32, so values encoded
+// by AppendUint32 and non-negative values encoded by AppendInt32 are
+// interchangeable and keep the order.
+//
+// You need to store the result of AppendUint32 like:
+//     dst = sortedbytes.AppendUint32(dst, value)
+func AppendUint32(dst []byte, value uint32) []byte {
+	if value == 0 {
+		return append(dst, typeCodeIntZero)
+	}
+
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], value)
+	return append(append(dst, typeCodePositiveInt32), b[:]...)
+}
+
+// TakeNullUint32 takes a NullUint32 value from b and returns it and the rest of b.
+func TakeNullUint32(b []byte) (value NullUint32, rest []byte, err error) {
+	var c byte
+	c, rest, err = takeTypeCode(b)
+	if err != nil {
+		return value, b, newNullDecodeError(b, err, expectedInt32)
+	}
+	if c == typeCodeNull {
+		return value, b[1:], nil
+	}
+	var v uint32
+	v, rest, err = takeUint32Value(c, rest)
+	if err != nil {
+		return value, b, newNullDecodeError(b, err, expectedInt32)
+	}
+	return NullUint32{Valid: true, Uint32: v}, rest, nil
+}
+
+// TakeUint32 takes an uint32 value from b and returns it and the rest of b.
+func TakeUint32(b []byte) (value uint32, rest []byte, err error) {
+	var c byte
+	c, rest, err = takeTypeCode(b)
+	if err != nil {
+		return 0, b, newDecodeError(b, err, expectedInt32)
+	}
+	value, rest, err = takeUint32Value(c, rest)
+	if err != nil {
+		return 0, b, newDecodeError(b, err, expectedInt32)
+	}
+	return value, rest, nil
+}
+
+func takeUint32Value(c byte, b []byte) (value uint32, rest []byte, err error) {
+	switch c {
+	case typeCodeIntZero:
+		return 0, b, nil
+	case typeCodePositiveInt32:
+		if len(b) < 4 {
+			return 0, nil, io.ErrUnexpectedEOF
+		}
+		return binary.BigEndian.Uint32(b[:4]), b[4:], nil
+	case typeCodeNegativeInt32:
+		return 0, nil, ErrValueOutOfRange
+	default:
+		return value, nil, ErrUnexpectedTypeCode
+	}
+}
+
+// NullUint64 represents an uint64 that may be null.
+// It is the unsigned counterpart of sql.NullInt64.
+type NullUint64 struct {
+	Uint64 uint64
+	Valid  bool // Valid is true if Uint64 is not NULL
+}
+
+// AppendNullUint64 appends a NullUint64 value to dst.
+//
+// You need to store the result of AppendNullUint64 like:
+//     dst = sortedbytes.AppendNullUint64(dst, value)
+func AppendNullUint64(dst []byte, value NullUint64) []byte {
+	if value.Valid {
+		return AppendUint64(dst, value.Uint64)
+	}
+	return append(dst, typeCodeNull)
+}
+
+// AppendUint64 appends an uint64 value to dst.
+//
+// The encoding uses the same type codes as AppendInt64, so values encoded
+// by AppendUint64 and values encoded by AppendInt64 are interchangeable
+// and keep the order. Values greater than math.MaxInt64 sort after all
+// int64 values.
+//
+// You need to store the result of AppendUint64 like:
+//     dst = sortedbytes.AppendUint64(dst, value)
+func AppendUint64(dst []byte, value uint64) []byte {
+	if value == 0 {
+		return append(dst, typeCodeIntZero)
+	}
+
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], value)
+	return append(append(dst, typeCodePositiveInt64), b[:]...)
+}
+
+// TakeNullUint64 takes a NullUint64 value from b and returns it and the rest of b.
+func TakeNullUint64(b []byte) (value NullUint64, rest []byte, err error) {
+	var c byte
+	c, rest, err = takeTypeCode(b)
+	if err != nil {
+		return value, b, newNullDecodeError(b, err, expectedInt64)
+	}
+	if c == typeCodeNull {
+		return value, b[1:], nil
+	}
+	var v uint64
+	v, rest, err = takeUint64Value(c, rest)
+	if err != nil {
+		return value, b, newNullDecodeError(b, err, expectedInt64)
+	}
+	return NullUint64{Valid: true, Uint64: v}, rest, nil
+}
+
+// TakeUint64 takes an uint64 value from b and returns it and the rest of b.
+func TakeUint64(b []byte) (value uint64, rest []byte, err error) {
+	var c byte
+	c, rest, err = takeTypeCode(b)
+	if err != nil {
+		return 0, b, newDecodeError(b, err, expectedInt64)
+	}
+	value, rest, err = takeUint64Value(c, rest)
+	if err != nil {
+		return 0, b, newDecodeError(b, err, expectedInt64)
+	}
+	return value, rest, nil
+}
+
+func takeUint64Value(c byte, b []byte) (value uint64, rest []byte, err error) {
+	switch c {
+	case typeCodeIntZero:
+		return 0, b, nil
+	case typeCodePositiveInt64:
+		if len(b) < 8 {
+			return 0, nil, io.ErrUnexpectedEOF
+		}
+		return binary.BigEndian.Uint64(b[:8]), b[8:], nil
+	case typeCodeNegativeInt64:
+		return 0, nil, ErrValueOutOfRange
+	default:
+		return value, nil, ErrUnexpectedTypeCode
 	}
 }
 
+// NullFloat32 represents a float32 that may be null.
+// It is the float32 counterpart of sql.NullFloat64.
+type NullFloat32 struct {
+	Float32 float32
+	Valid   bool // Valid is true if Float32 is not NULL
+}
+
+// AppendNullFloat32 appends a NullFloat32 value to dst.
+//
+// You need to store the result of AppendNullFloat32 like:
+//     dst = sortedbytes.AppendNullFloat32(dst, value)
+func AppendNullFloat32(dst []byte, value NullFloat32) []byte {
+	if value.Valid {
+		return AppendFloat32(dst, value.Float32)
+	}
+	return append(dst, typeCodeNull)
+}
+
+// AppendFloat32 appends a float32 value to dst.
+//
+// The value is encoded in the same way as AppendFloat64 but in 4 bytes
+// with the FDB float type code 0x20, so all float32 values sort before
+// all float64 values.
+//
+// You need to store the result of AppendFloat32 like:
+//     dst = sortedbytes.AppendFloat32(dst, value)
+func AppendFloat32(dst []byte, value float32) []byte {
+	v := math.Float32bits(value)
+	if v&0x8000_0000 == 0 {
+		v ^= 0x8000_0000
+	} else {
+		v ^= 0xffff_ffff
+	}
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], v)
+	return append(append(dst, typeCodeFloat32), b[:]...)
+}
+
+// TakeNullFloat32 takes a NullFloat32 value from b and returns it and the rest of b.
+func TakeNullFloat32(b []byte) (value NullFloat32, rest []byte, err error) {
+	var c byte
+	c, rest, err = takeTypeCode(b)
+	if err != nil {
+		return value, b, newNullDecodeError(b, err, expectedFloat32)
+	}
+	if c == typeCodeNull {
+		return value, b[1:], nil
+	}
+	if c != typeCodeFloat32 {
+		return value, b, newNullDecodeError(b, ErrUnexpectedTypeCode, expectedFloat32)
+	}
+	var v float32
+	v, rest, err = takeFloat32Value(rest)
+	if err != nil {
+		return value, b, newNullDecodeError(b, err, expectedFloat32)
+	}
+	return NullFloat32{Valid: true, Float32: v}, rest, nil
+}
+
+// TakeFloat32 takes a float32 value from b and returns it and the rest of b.
+func TakeFloat32(b []byte) (value float32, rest []byte, err error) {
+	rest, err = expectTypeCode(b, typeCodeFloat32)
+	if err != nil {
+		return 0, b, newDecodeError(b, err, expectedFloat32)
+	}
+	value, rest, err = takeFloat32Value(rest)
+	if err != nil {
+		return 0, b, newDecodeError(b, err, expectedFloat32)
+	}
+	return value, rest, nil
+}
+
+func takeFloat32Value(b []byte) (value float32, rest []byte, err error) {
+	if len(b) < 4 {
+		return 0, nil, io.ErrUnexpectedEOF
+	}
+	v := binary.BigEndian.Uint32(b[:4])
+	if v&0x8000_0000 != 0 {
+		v ^= 0x8000_0000
+	} else {
+		v ^= 0xffff_ffff
+	}
+	return math.Float32frombits(v), b[4:], nil
+}
+
 // AppendNullFloat64 appends a NullFloat64 value to dst.
 //
 // You need to store the result of AppendNullFloat64 like:
@@ -338,15 +749,18 @@ func TakeNullFloat64(b []byte) (value sql.NullFloat64, rest []byte, err error) {
 	var c byte
 	c, rest, err = takeTypeCode(b)
 	if err != nil {
-		return value, b, err
+		return value, b, newNullDecodeError(b, err, expectedFloat64)
 	}
 	if c == typeCodeNull {
 		return value, b[1:], nil
 	}
+	if c != typeCodeFloat64 {
+		return value, b, newNullDecodeError(b, ErrUnexpectedTypeCode, expectedFloat64)
+	}
 	var v float64
 	v, rest, err = takeFloat64Value(rest)
 	if err != nil {
-		return value, b, err
+		return value, b, newNullDecodeError(b, err, expectedFloat64)
 	}
 	return sql.NullFloat64{Valid: true, Float64: v}, rest, nil
 }
@@ -355,11 +769,11 @@ func TakeNullFloat64(b []byte) (value sql.NullFloat64, rest []byte, err error) {
 func TakeFloat64(b []byte) (value float64, rest []byte, err error) {
 	rest, err = expectTypeCode(b, typeCodeFloat64)
 	if err != nil {
-		return 0, b, err
+		return 0, b, newDecodeError(b, err, expectedFloat64)
 	}
 	value, rest, err = takeFloat64Value(rest)
 	if err != nil {
-		return 0, b, err
+		return 0, b, newDecodeError(b, err, expectedFloat64)
 	}
 	return value, rest, nil
 }
@@ -404,7 +818,7 @@ func TakeNullBool(b []byte) (value sql.NullBool, rest []byte, err error) {
 	var c byte
 	c, rest, err = takeTypeCode(b)
 	if err != nil {
-		return value, b, err
+		return value, b, newNullDecodeError(b, err, expectedBool)
 	}
 	if c == typeCodeNull {
 		return value, b[1:], nil
@@ -412,7 +826,7 @@ func TakeNullBool(b []byte) (value sql.NullBool, rest []byte, err error) {
 	var v bool
 	v, rest, err = takeBoolValue(c, rest)
 	if err != nil {
-		return value, b, err
+		return value, b, newNullDecodeError(b, err, expectedBool)
 	}
 	return sql.NullBool{Valid: true, Bool: v}, rest, nil
 }
@@ -422,11 +836,11 @@ func TakeBool(b []byte) (value bool, rest []byte, err error) {
 	var c byte
 	c, rest, err = takeTypeCode(b)
 	if err != nil {
-		return false, b, err
+		return false, b, newDecodeError(b, err, expectedBool)
 	}
 	value, rest, err = takeBoolValue(c, rest)
 	if err != nil {
-		return false, b, err
+		return false, b, newDecodeError(b, err, expectedBool)
 	}
 	return value, rest, nil
 }
@@ -438,7 +852,7 @@ func takeBoolValue(c byte, b []byte) (value bool, rest []byte, err error) {
 	case typeCodeFalse:
 		return false, b, nil
 	default:
-		return value, nil, errUnpexptedTypeCode
+		return value, nil, ErrUnexpectedTypeCode
 	}
 }
 
@@ -449,11 +863,28 @@ func expectTypeCode(b []byte, typeCode byte) (rest []byte, err error) {
 		return nil, err
 	}
 	if c != typeCode {
-		return nil, errUnpexptedTypeCode
+		return nil, ErrUnexpectedTypeCode
 	}
 	return rest, nil
 }
 
+// intPayloadLen returns the length of the payload which follows the integer
+// type code c between typeCodeNegativeInt64 and typeCodePositiveInt64.
+//
+// Note typeCodeNegativeInt32 and typeCodePositiveInt32 are followed by
+// 4 bytes in this package while they are followed by 5 bytes in the FDB
+// tuple layer.
+func intPayloadLen(c byte) int {
+	switch {
+	case c == typeCodeNegativeInt32 || c == typeCodePositiveInt32:
+		return 4
+	case c < typeCodeIntZero:
+		return int(typeCodeIntZero - c)
+	default:
+		return int(c - typeCodeIntZero)
+	}
+}
+
 func takeTypeCode(b []byte) (typeCode byte, rest []byte, err error) {
 	if len(b) < 1 {
 		return 0, nil, io.ErrUnexpectedEOF
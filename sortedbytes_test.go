@@ -3,6 +3,7 @@ package sortedbytes_test
 import (
 	"bytes"
 	"database/sql"
+	"errors"
 	"math"
 	"reflect"
 	"testing"
@@ -155,6 +156,243 @@ func TestTakeString(t *testing.T) {
 	})
 }
 
+func TestTakeStringBytes(t *testing.T) {
+	t.Run("roundtrip", func(t *testing.T) {
+		testCases := []struct {
+			input string
+			alias bool
+		}{
+			{input: "", alias: true},
+			{input: "foo", alias: true},
+			{input: "F\u00d4O\u00ffbar", alias: true},
+			{input: "F\u00d4O\u0000bar", alias: false},
+			{input: "\x00", alias: false},
+			{input: "\x00\x00\xff\xff", alias: false},
+		}
+		var buf []byte
+		for i, tc := range testCases {
+			b := sortedbytes.AppendString([]byte(nil), tc.input)
+			b = append(b, "rest"...)
+			v, rest, err := sortedbytes.TakeStringBytes(b, buf)
+			if err != nil {
+				t.Errorf("case %d: got error: %s", i, err)
+				continue
+			}
+			if got, want := string(v), tc.input; got != want {
+				t.Errorf("case %d: string unmatch: got=%q, want=%q", i, got, want)
+			}
+			if got, want := string(rest), "rest"; got != want {
+				t.Errorf("case %d: rest unmatch: got=%q, want=%q", i, got, want)
+			}
+			if len(v) > 0 {
+				if got, want := &v[0] == &b[1], tc.alias; got != want {
+					t.Errorf("case %d: alias unmatch: got=%v, want=%v", i, got, want)
+				}
+			}
+			if !tc.alias {
+				buf = v
+			}
+		}
+	})
+	t.Run("allocs", func(t *testing.T) {
+		b := sortedbytes.AppendString(nil, "foo\x00bar")
+		b = sortedbytes.AppendString(b, "baz")
+		buf := make([]byte, 0, 16)
+		allocs := testing.AllocsPerRun(10, func() {
+			v, rest, err := sortedbytes.TakeStringBytes(b, buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, _, err := sortedbytes.TakeStringBytes(rest, v); err != nil {
+				t.Fatal(err)
+			}
+		})
+		if allocs != 0 {
+			t.Errorf("allocs unmatch: got=%v, want=0", allocs)
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		testCases := [][]byte{
+			[]byte(""),
+			[]byte("\x01foo\x00"),
+			[]byte("\x02"),
+			[]byte("\x02foo"),
+			[]byte("\x02\x00\xffa"),
+		}
+		for i, input := range testCases {
+			v, rest, err := sortedbytes.TakeStringBytes(input, nil)
+			if err == nil {
+				t.Errorf("case %d: got no error", i)
+			}
+			if v != nil {
+				t.Errorf("case %d: value unmatch: got=%q, want=nil", i, v)
+			}
+			if !bytes.Equal(rest, input) {
+				t.Errorf("case %d: rest unmatch: got=0x%x, want=0x%x", i, rest, input)
+			}
+		}
+	})
+}
+
+func TestAppendNullBytes(t *testing.T) {
+	t.Run("order", func(t *testing.T) {
+		testCases := []struct {
+			a, b sortedbytes.NullBytes
+		}{
+			{
+				a: sortedbytes.NullBytes{Valid: false, Bytes: nil},
+				b: sortedbytes.NullBytes{Valid: true, Bytes: nil},
+			},
+			{
+				a: sortedbytes.NullBytes{Valid: true, Bytes: []byte{}},
+				b: sortedbytes.NullBytes{Valid: true, Bytes: []byte{0x00}},
+			},
+			{
+				a: sortedbytes.NullBytes{Valid: true, Bytes: []byte{0x00}},
+				b: sortedbytes.NullBytes{Valid: true, Bytes: []byte{0x00, 0x00}},
+			},
+			{
+				a: sortedbytes.NullBytes{Valid: true, Bytes: []byte{0xfe}},
+				b: sortedbytes.NullBytes{Valid: true, Bytes: []byte{0xff}},
+			},
+		}
+		for i, tc := range testCases {
+			a := sortedbytes.AppendNullBytes([]byte(nil), tc.a)
+			b := sortedbytes.AppendNullBytes([]byte(nil), tc.b)
+			if got, want := bytes.Compare(a, b), -1; got != want {
+				t.Errorf("case %d: compare result unmatch: got=%d, want=%d, a=0x%x, b=0x%x",
+					i, got, want, a, b)
+			}
+		}
+	})
+}
+
+func TestTakeNullBytes(t *testing.T) {
+	t.Run("roundtrip", func(t *testing.T) {
+		testCases := []sortedbytes.NullBytes{
+			{Valid: false, Bytes: nil},
+			{Valid: true, Bytes: []byte{}},
+			{Valid: true, Bytes: []byte("foo")},
+			{Valid: true, Bytes: []byte("\x00")},
+			{Valid: true, Bytes: []byte("\x00\xff")},
+			{Valid: true, Bytes: []byte("\xff\x00\x00\xff")},
+		}
+		for i, input := range testCases {
+			b := sortedbytes.AppendNullBytes([]byte(nil), input)
+			v, rest, err := sortedbytes.TakeNullBytes(b)
+			if err != nil {
+				t.Errorf("case %d: got error: %s", i, err)
+			}
+			if got, want := v, input; !reflect.DeepEqual(got, want) {
+				t.Errorf("case %d: value unmatch: got=%+v, want=%+v", i, got, want)
+			}
+			if got, want := len(rest), 0; got != want {
+				t.Errorf("case %d: rest length unmatch: got=%d, want=%d", i, got, want)
+			}
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		testCases := [][]byte{
+			[]byte("\x01"),
+			[]byte("\x01foo"),
+			[]byte("\x01\x00\xffa"),
+			[]byte("\x02foo\x00"),
+		}
+		for i, input := range testCases {
+			_, _, err := sortedbytes.TakeNullBytes(input)
+			if err == nil {
+				t.Errorf("case %d: got no error", i)
+			}
+		}
+	})
+}
+
+func TestAppendBytes(t *testing.T) {
+	t.Run("order", func(t *testing.T) {
+		testCases := []struct {
+			a, b []byte
+		}{
+			{a: []byte(""), b: []byte("\x00")},
+			{a: []byte(""), b: []byte("a")},
+			{a: []byte("a"), b: []byte("a\x00")},
+			{a: []byte("a\x00"), b: []byte("a\x01")},
+			{a: []byte("bar"), b: []byte("bb")},
+			{a: []byte("\xff"), b: []byte("\xff\x00")},
+		}
+		for i, tc := range testCases {
+			a := sortedbytes.AppendBytes([]byte(nil), tc.a)
+			b := sortedbytes.AppendBytes([]byte(nil), tc.b)
+			if got, want := bytes.Compare(a, b), -1; got != want {
+				t.Errorf("case %d: compare result unmatch: got=%d, want=%d, a=0x%x, b=0x%x",
+					i, got, want, a, b)
+			}
+		}
+	})
+	t.Run("orderWithString", func(t *testing.T) {
+		a := sortedbytes.AppendBytes([]byte(nil), []byte("\xff\xff"))
+		b := sortedbytes.AppendString([]byte(nil), "")
+		if got, want := bytes.Compare(a, b), -1; got != want {
+			t.Errorf("compare result unmatch: got=%d, want=%d, a=0x%x, b=0x%x", got, want, a, b)
+		}
+	})
+}
+
+func TestTakeBytes(t *testing.T) {
+	t.Run("roundtrip", func(t *testing.T) {
+		testCases := [][]byte{
+			[]byte(""),
+			[]byte("foo"),
+			[]byte("\x00foo"),
+			[]byte("foo\x00"),
+			[]byte("f\x00\x00oo"),
+			[]byte("\x00"),
+			[]byte("\x00\x00"),
+			[]byte("\xff"),
+			[]byte("\xff\xff"),
+			[]byte("\x00\xff"),
+			[]byte("\x00\x00\xff\xff"),
+		}
+		for i, input := range testCases {
+			b := sortedbytes.AppendBytes([]byte(nil), input)
+			v, rest, err := sortedbytes.TakeBytes(b)
+			if err != nil {
+				t.Errorf("case %d: got error: %s", i, err)
+			}
+			if got, want := v, input; !bytes.Equal(got, want) {
+				t.Errorf("case %d: value unmatch: got=%q, want=%q", i, got, want)
+			}
+			if got, want := len(rest), 0; got != want {
+				t.Errorf("case %d: rest length unmatch: got=%d, want=%d", i, got, want)
+			}
+		}
+	})
+	t.Run("noAlias", func(t *testing.T) {
+		b := sortedbytes.AppendBytes([]byte(nil), []byte("foo"))
+		v, _, err := sortedbytes.TakeBytes(b)
+		if err != nil {
+			t.Fatalf("got error: %s", err)
+		}
+		b[1] = 'x'
+		if got, want := string(v), "foo"; got != want {
+			t.Errorf("value modified via input: got=%q, want=%q", got, want)
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		testCases := [][]byte{
+			[]byte("\x01"),
+			[]byte("\x01foo"),
+			[]byte("\x01\x00\xffa"),
+			[]byte("\x02foo\x00"),
+		}
+		for i, input := range testCases {
+			_, _, err := sortedbytes.TakeBytes(input)
+			if err == nil {
+				t.Errorf("case %d: got no error", i)
+			}
+		}
+	})
+}
+
 func TestAppendNullInt32(t *testing.T) {
 	t.Run("order", func(t *testing.T) {
 		testCases := []struct {
@@ -495,6 +733,478 @@ func TestTakeInt64(t *testing.T) {
 	})
 }
 
+func TestAppendNullUint32(t *testing.T) {
+	t.Run("order", func(t *testing.T) {
+		testCases := []struct {
+			a, b sortedbytes.NullUint32
+		}{
+			{
+				a: sortedbytes.NullUint32{Valid: false, Uint32: 0},
+				b: sortedbytes.NullUint32{Valid: true, Uint32: 0},
+			},
+			{
+				a: sortedbytes.NullUint32{Valid: true, Uint32: 0},
+				b: sortedbytes.NullUint32{Valid: true, Uint32: 1},
+			},
+			{
+				a: sortedbytes.NullUint32{Valid: true, Uint32: 1},
+				b: sortedbytes.NullUint32{Valid: true, Uint32: 2},
+			},
+			{
+				a: sortedbytes.NullUint32{Valid: true, Uint32: math.MaxInt32},
+				b: sortedbytes.NullUint32{Valid: true, Uint32: math.MaxInt32 + 1},
+			},
+			{
+				a: sortedbytes.NullUint32{Valid: true, Uint32: math.MaxUint32 - 1},
+				b: sortedbytes.NullUint32{Valid: true, Uint32: math.MaxUint32},
+			},
+		}
+		for i, tc := range testCases {
+			a := sortedbytes.AppendNullUint32([]byte(nil), tc.a)
+			b := sortedbytes.AppendNullUint32([]byte(nil), tc.b)
+			if got, want := bytes.Compare(a, b), -1; got != want {
+				t.Errorf("case %d: compare result unmatch: got=%d, want=%d, a=%+v, b=%+v",
+					i, got, want, a, b)
+			}
+		}
+	})
+}
+
+func TestTakeNullUint32(t *testing.T) {
+	t.Run("roundtrip", func(t *testing.T) {
+		testCases := []sortedbytes.NullUint32{
+			{Valid: false, Uint32: 0},
+			{Valid: true, Uint32: 0},
+			{Valid: true, Uint32: 1},
+			{Valid: true, Uint32: math.MaxInt32},
+			{Valid: true, Uint32: math.MaxInt32 + 1},
+			{Valid: true, Uint32: math.MaxUint32},
+		}
+		for i, input := range testCases {
+			b := sortedbytes.AppendNullUint32([]byte(nil), input)
+			v, rest, err := sortedbytes.TakeNullUint32(b)
+			if err != nil {
+				t.Errorf("case %d: got error: %s", i, err)
+			}
+			if got, want := v, input; !reflect.DeepEqual(got, want) {
+				t.Errorf("case %d: value unmatch: got=%+v, want=%+v", i, got, want)
+			}
+			if got, want := len(rest), 0; got != want {
+				t.Errorf("case %d: rest length unmatch: got=%d, want=%d", i, got, want)
+			}
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		testCases := [][]byte{
+			[]byte("\x19"),
+			[]byte("\x19\x01\x02\x03"),
+			[]byte("\x0f\xff\xff\xff\xfe"),
+			[]byte("\x1c\x00\x00\x00\x00\x00\x00\x00\x01"),
+		}
+		for i, input := range testCases {
+			_, _, err := sortedbytes.TakeNullUint32(input)
+			if err == nil {
+				t.Errorf("case %d: got no error", i)
+			}
+		}
+	})
+}
+
+func TestAppendUint32(t *testing.T) {
+	t.Run("order", func(t *testing.T) {
+		testCases := []struct {
+			a, b uint32
+		}{
+			{a: 0, b: 1},
+			{a: 1, b: 2},
+			{a: 0xff, b: 0x100},
+			{a: math.MaxInt32, b: math.MaxInt32 + 1},
+			{a: math.MaxUint32 - 1, b: math.MaxUint32},
+			{a: 0, b: math.MaxUint32},
+		}
+		for i, tc := range testCases {
+			a := sortedbytes.AppendUint32([]byte(nil), tc.a)
+			b := sortedbytes.AppendUint32([]byte(nil), tc.b)
+			if got, want := bytes.Compare(a, b), -1; got != want {
+				t.Errorf("case %d: compare result unmatch: got=%d, want=%d, a=%d, b=%d",
+					i, got, want, a, b)
+			}
+		}
+	})
+	t.Run("orderWithInt32", func(t *testing.T) {
+		testCases := []struct {
+			a int32
+			b uint32
+		}{
+			{a: math.MinInt32, b: 0},
+			{a: -1, b: 0},
+			{a: 0, b: 1},
+			{a: math.MaxInt32, b: math.MaxInt32 + 1},
+		}
+		for i, tc := range testCases {
+			a := sortedbytes.AppendInt32([]byte(nil), tc.a)
+			b := sortedbytes.AppendUint32([]byte(nil), tc.b)
+			if got, want := bytes.Compare(a, b), -1; got != want {
+				t.Errorf("case %d: compare result unmatch: got=%d, want=%d, a=%d, b=%d",
+					i, got, want, a, b)
+			}
+		}
+	})
+}
+
+func TestTakeUint32(t *testing.T) {
+	t.Run("roundtrip", func(t *testing.T) {
+		testCases := []uint32{
+			0,
+			1,
+			math.MaxInt32,
+			math.MaxInt32 + 1,
+			math.MaxUint32 - 1,
+			math.MaxUint32,
+		}
+		for i, input := range testCases {
+			b := sortedbytes.AppendUint32([]byte(nil), input)
+			v, rest, err := sortedbytes.TakeUint32(b)
+			if err != nil {
+				t.Errorf("case %d: got error: %s", i, err)
+			}
+			if got, want := v, input; got != want {
+				t.Errorf("case %d: value unmatch: got=%d, want=%d", i, got, want)
+			}
+			if got, want := len(rest), 0; got != want {
+				t.Errorf("case %d: rest length unmatch: got=%d, want=%d", i, got, want)
+			}
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		testCases := [][]byte{
+			[]byte("\x19"),
+			[]byte("\x19\x01\x02\x03"),
+			[]byte("\x0f\xff\xff\xff\xfe"),
+			[]byte("\x02"),
+		}
+		for i, input := range testCases {
+			_, _, err := sortedbytes.TakeUint32(input)
+			if err == nil {
+				t.Errorf("case %d: got no error", i)
+			}
+		}
+	})
+}
+
+func TestAppendNullUint64(t *testing.T) {
+	t.Run("order", func(t *testing.T) {
+		testCases := []struct {
+			a, b sortedbytes.NullUint64
+		}{
+			{
+				a: sortedbytes.NullUint64{Valid: false, Uint64: 0},
+				b: sortedbytes.NullUint64{Valid: true, Uint64: 0},
+			},
+			{
+				a: sortedbytes.NullUint64{Valid: true, Uint64: 0},
+				b: sortedbytes.NullUint64{Valid: true, Uint64: 1},
+			},
+			{
+				a: sortedbytes.NullUint64{Valid: true, Uint64: math.MaxInt64},
+				b: sortedbytes.NullUint64{Valid: true, Uint64: math.MaxInt64 + 1},
+			},
+			{
+				a: sortedbytes.NullUint64{Valid: true, Uint64: math.MaxUint64 - 1},
+				b: sortedbytes.NullUint64{Valid: true, Uint64: math.MaxUint64},
+			},
+		}
+		for i, tc := range testCases {
+			a := sortedbytes.AppendNullUint64([]byte(nil), tc.a)
+			b := sortedbytes.AppendNullUint64([]byte(nil), tc.b)
+			if got, want := bytes.Compare(a, b), -1; got != want {
+				t.Errorf("case %d: compare result unmatch: got=%d, want=%d, a=%+v, b=%+v",
+					i, got, want, a, b)
+			}
+		}
+	})
+}
+
+func TestTakeNullUint64(t *testing.T) {
+	t.Run("roundtrip", func(t *testing.T) {
+		testCases := []sortedbytes.NullUint64{
+			{Valid: false, Uint64: 0},
+			{Valid: true, Uint64: 0},
+			{Valid: true, Uint64: 1},
+			{Valid: true, Uint64: math.MaxInt64},
+			{Valid: true, Uint64: math.MaxInt64 + 1},
+			{Valid: true, Uint64: math.MaxUint64},
+		}
+		for i, input := range testCases {
+			b := sortedbytes.AppendNullUint64([]byte(nil), input)
+			v, rest, err := sortedbytes.TakeNullUint64(b)
+			if err != nil {
+				t.Errorf("case %d: got error: %s", i, err)
+			}
+			if got, want := v, input; !reflect.DeepEqual(got, want) {
+				t.Errorf("case %d: value unmatch: got=%+v, want=%+v", i, got, want)
+			}
+			if got, want := len(rest), 0; got != want {
+				t.Errorf("case %d: rest length unmatch: got=%d, want=%d", i, got, want)
+			}
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		testCases := [][]byte{
+			[]byte("\x1c"),
+			[]byte("\x1c\x01\x02\x03\x04\x05\x06\x07"),
+			[]byte("\x0c\xff\xff\xff\xff\xff\xff\xff\xfe"),
+			[]byte("\x19\x00\x00\x00\x01"),
+		}
+		for i, input := range testCases {
+			_, _, err := sortedbytes.TakeNullUint64(input)
+			if err == nil {
+				t.Errorf("case %d: got no error", i)
+			}
+		}
+	})
+}
+
+func TestAppendUint64(t *testing.T) {
+	t.Run("order", func(t *testing.T) {
+		testCases := []struct {
+			a, b uint64
+		}{
+			{a: 0, b: 1},
+			{a: 1, b: 2},
+			{a: 0xff, b: 0x100},
+			{a: math.MaxInt64, b: math.MaxInt64 + 1},
+			{a: math.MaxUint64 - 1, b: math.MaxUint64},
+			{a: 0, b: math.MaxUint64},
+		}
+		for i, tc := range testCases {
+			a := sortedbytes.AppendUint64([]byte(nil), tc.a)
+			b := sortedbytes.AppendUint64([]byte(nil), tc.b)
+			if got, want := bytes.Compare(a, b), -1; got != want {
+				t.Errorf("case %d: compare result unmatch: got=%d, want=%d, a=%d, b=%d",
+					i, got, want, a, b)
+			}
+		}
+	})
+	t.Run("orderWithInt64", func(t *testing.T) {
+		testCases := []struct {
+			a int64
+			b uint64
+		}{
+			{a: math.MinInt64, b: 0},
+			{a: -1, b: 0},
+			{a: 0, b: 1},
+			{a: 1, b: 2},
+			{a: math.MaxInt64, b: math.MaxInt64 + 1},
+			{a: math.MaxInt64, b: math.MaxUint64},
+		}
+		for i, tc := range testCases {
+			a := sortedbytes.AppendInt64([]byte(nil), tc.a)
+			b := sortedbytes.AppendUint64([]byte(nil), tc.b)
+			if got, want := bytes.Compare(a, b), -1; got != want {
+				t.Errorf("case %d: compare result unmatch: got=%d, want=%d, a=%d, b=%d",
+					i, got, want, a, b)
+			}
+		}
+	})
+}
+
+func TestTakeUint64(t *testing.T) {
+	t.Run("roundtrip", func(t *testing.T) {
+		testCases := []uint64{
+			0,
+			1,
+			math.MaxInt64,
+			math.MaxInt64 + 1,
+			math.MaxUint64 - 1,
+			math.MaxUint64,
+		}
+		for i, input := range testCases {
+			b := sortedbytes.AppendUint64([]byte(nil), input)
+			v, rest, err := sortedbytes.TakeUint64(b)
+			if err != nil {
+				t.Errorf("case %d: got error: %s", i, err)
+			}
+			if got, want := v, input; got != want {
+				t.Errorf("case %d: value unmatch: got=%d, want=%d", i, got, want)
+			}
+			if got, want := len(rest), 0; got != want {
+				t.Errorf("case %d: rest length unmatch: got=%d, want=%d", i, got, want)
+			}
+		}
+	})
+	t.Run("fromInt64", func(t *testing.T) {
+		testCases := []int64{0, 1, math.MaxInt64}
+		for i, input := range testCases {
+			b := sortedbytes.AppendInt64([]byte(nil), input)
+			v, _, err := sortedbytes.TakeUint64(b)
+			if err != nil {
+				t.Errorf("case %d: got error: %s", i, err)
+			}
+			if got, want := v, uint64(input); got != want {
+				t.Errorf("case %d: value unmatch: got=%d, want=%d", i, got, want)
+			}
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		testCases := [][]byte{
+			[]byte("\x1c"),
+			[]byte("\x1c\x01\x02\x03\x04\x05\x06\x07"),
+			[]byte("\x0c\xff\xff\xff\xff\xff\xff\xff\xfe"),
+			[]byte("\x02"),
+		}
+		for i, input := range testCases {
+			_, _, err := sortedbytes.TakeUint64(input)
+			if err == nil {
+				t.Errorf("case %d: got no error", i)
+			}
+		}
+	})
+}
+
+func TestAppendNullFloat32(t *testing.T) {
+	t.Run("order", func(t *testing.T) {
+		testCases := []sortedbytes.NullFloat32{
+			{Valid: false, Float32: 0},
+			{Valid: true, Float32: float32(math.Inf(-1))},
+			{Valid: true, Float32: -math.MaxFloat32},
+			{Valid: true, Float32: -1},
+			{Valid: true, Float32: 0},
+			{Valid: true, Float32: 1},
+			{Valid: true, Float32: math.MaxFloat32},
+			{Valid: true, Float32: float32(math.Inf(1))},
+			{Valid: true, Float32: float32(math.NaN())},
+		}
+		for i := 1; i < len(testCases); i++ {
+			a := sortedbytes.AppendNullFloat32([]byte(nil), testCases[i-1])
+			b := sortedbytes.AppendNullFloat32([]byte(nil), testCases[i])
+			if got, want := bytes.Compare(a, b), -1; got != want {
+				t.Errorf("case %d: compare result unmatch: got=%d, want=%d, a=0x%x, b=0x%x",
+					i, got, want, a, b)
+			}
+		}
+	})
+}
+
+func TestTakeNullFloat32(t *testing.T) {
+	t.Run("roundtrip", func(t *testing.T) {
+		testCases := []sortedbytes.NullFloat32{
+			{Valid: false, Float32: 0},
+			{Valid: true, Float32: -1.5},
+			{Valid: true, Float32: 0},
+			{Valid: true, Float32: math.MaxFloat32},
+		}
+		for i, input := range testCases {
+			b := sortedbytes.AppendNullFloat32([]byte(nil), input)
+			v, rest, err := sortedbytes.TakeNullFloat32(b)
+			if err != nil {
+				t.Errorf("case %d: got error: %s", i, err)
+			}
+			if got, want := v, input; got != want {
+				t.Errorf("case %d: value unmatch: got=%v, want=%v", i, got, want)
+			}
+			if got, want := len(rest), 0; got != want {
+				t.Errorf("case %d: rest length unmatch: got=%d, want=%d", i, got, want)
+			}
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		testCases := [][]byte{
+			[]byte("\x20\x01\x02\x03"),
+			[]byte("\x21\x01\x02\x03\x04\x05\x06\x07\x08"),
+		}
+		for i, input := range testCases {
+			_, _, err := sortedbytes.TakeNullFloat32(input)
+			if err == nil {
+				t.Errorf("case %d: got no error", i)
+			}
+		}
+	})
+}
+
+func TestAppendFloat32(t *testing.T) {
+	t.Run("order", func(t *testing.T) {
+		testCases := []float32{
+			float32(math.Inf(-1)),
+			-math.MaxFloat32,
+			math.Nextafter32(-math.MaxFloat32, 0),
+			-2,
+			-1,
+			-math.SmallestNonzeroFloat32,
+			0,
+			math.SmallestNonzeroFloat32,
+			1,
+			2,
+			math.Nextafter32(math.MaxFloat32, 0),
+			math.MaxFloat32,
+			float32(math.Inf(1)),
+			float32(math.NaN()),
+		}
+		for i := 1; i < len(testCases); i++ {
+			a := sortedbytes.AppendFloat32([]byte(nil), testCases[i-1])
+			b := sortedbytes.AppendFloat32([]byte(nil), testCases[i])
+			if got, want := bytes.Compare(a, b), -1; got != want {
+				t.Errorf("case %d: compare result unmatch: got=%d, want=%d, a=0x%x, b=0x%x",
+					i, got, want, a, b)
+			}
+		}
+	})
+	t.Run("beforeFloat64", func(t *testing.T) {
+		a := sortedbytes.AppendFloat32([]byte(nil), float32(math.NaN()))
+		b := sortedbytes.AppendFloat64([]byte(nil), math.Inf(-1))
+		if got, want := bytes.Compare(a, b), -1; got != want {
+			t.Errorf("compare result unmatch: got=%d, want=%d, a=0x%x, b=0x%x", got, want, a, b)
+		}
+	})
+}
+
+func TestTakeFloat32(t *testing.T) {
+	t.Run("roundtrip", func(t *testing.T) {
+		testCases := []float32{
+			float32(math.NaN()),
+			float32(math.Inf(-1)),
+			-math.MaxFloat32,
+			-1.5,
+			-math.SmallestNonzeroFloat32,
+			math.Float32frombits(0x8000_0000),
+			0,
+			math.SmallestNonzeroFloat32,
+			0.1,
+			math.MaxFloat32,
+			float32(math.Inf(1)),
+		}
+		for i, input := range testCases {
+			b := sortedbytes.AppendFloat32([]byte(nil), input)
+			v, rest, err := sortedbytes.TakeFloat32(b)
+			if err != nil {
+				t.Errorf("case %d: got error: %s", i, err)
+			}
+			if got, want := v, input; math.Float32bits(got) != math.Float32bits(want) {
+				t.Errorf("case %d: value unmatch: got=%v, want=%v", i, got, want)
+			}
+			if got, want := len(rest), 0; got != want {
+				t.Errorf("case %d: rest length unmatch: got=%d, want=%d", i, got, want)
+			}
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		testCases := [][]byte{
+			nil,
+			[]byte("\x20\x01\x02\x03"),
+			sortedbytes.AppendFloat64([]byte(nil), 1),
+		}
+		for i, input := range testCases {
+			v, rest, err := sortedbytes.TakeFloat32(input)
+			if err == nil {
+				t.Errorf("case %d: got no error", i)
+			}
+			if v != 0 || !bytes.Equal(rest, input) {
+				t.Errorf("case %d: unexpected result on error: v=%v, rest=0x%x", i, v, rest)
+			}
+		}
+	})
+}
+
 func TestAppendNullFloat64(t *testing.T) {
 	t.Run("order", func(t *testing.T) {
 		testCases := []struct {
@@ -611,6 +1321,8 @@ func TestTakeNullFloat64(t *testing.T) {
 	t.Run("invalid", func(t *testing.T) {
 		testCases := [][]byte{
 			[]byte("\x21\x01\x02\x03\x04\x05\x06\x07"),
+			[]byte("\x20\x01\x02\x03\x04"),
+			sortedbytes.AppendString(nil, "abcdefgh"),
 		}
 		for i, input := range testCases {
 			_, _, err := sortedbytes.TakeNullFloat64(input)
@@ -618,6 +1330,11 @@ func TestTakeNullFloat64(t *testing.T) {
 				t.Errorf("case %d: got no error", i)
 			}
 		}
+		for i, input := range testCases[1:] {
+			if _, _, err := sortedbytes.TakeNullFloat64(input); !errors.Is(err, sortedbytes.ErrUnexpectedTypeCode) {
+				t.Errorf("case %d: error unmatch: got=%v, want=%v", i+1, err, sortedbytes.ErrUnexpectedTypeCode)
+			}
+		}
 	})
 }
 
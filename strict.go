@@ -0,0 +1,279 @@
+package sortedbytes
+
+import (
+	"bytes"
+	"math/big"
+	"unicode/utf8"
+)
+
+// Validate reports whether b consists only of values encoded in the form
+// which the Append functions produce, so that no other bytes decode to
+// the same values.
+//
+// Validate accepts the values encoded by any Append function including
+// the Desc counterparts, and nested tuples encoded by AppendNested.
+// It rejects integers with a zero magnitude in a non-zero type code,
+// integers written in more bytes than needed except in the fixed size
+// forms of AppendInt32 and AppendInt64, negative integers in the fixed
+// size form of AppendInt32 which do not fit in int32, big integers which
+// fit in 64 bits or have leading zero bytes, strings which are not valid
+// UTF-8, and times whose nanoseconds are out of range.
+//
+// Since Validate does not know the types of the key components, it cannot
+// tell whether an integer was encoded by AppendVarInt or AppendInt64 for
+// example. Use the strict Take functions like TakeInt64Strict to check
+// the form of each component as well.
+//
+// If Validate fails, the returned error is a *DecodeError whose Offset is
+// the offset in b.
+func Validate(b []byte) error {
+	rest := b
+	for len(rest) > 0 {
+		n, err := validValueLen(rest)
+		if err != nil {
+			return AddOffset(newDecodeError(rest, err, nil), len(b)-len(rest))
+		}
+		rest = rest[n:]
+	}
+	return nil
+}
+
+// validValueLen returns the length of the encoded value at the start of b
+// if the value is valid.
+func validValueLen(b []byte) (int, error) {
+	n, err := encodedLen(b)
+	if err != nil {
+		return 0, err
+	}
+	if err := validateValue(b[:n]); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// validateValue validates v which is exactly one encoded value.
+func validateValue(v []byte) error {
+	c := v[0]
+	switch {
+	case c == typeCodeUTF8String:
+		if !validEscapedUTF8(v[1 : len(v)-1]) {
+			return ErrInvalidUTF8
+		}
+		return nil
+	case c == ^byte(typeCodeUTF8String):
+		s, _, err := takeDescEscapedValue(v[1:])
+		if err != nil {
+			return err
+		}
+		if !utf8.Valid(s) {
+			return ErrInvalidUTF8
+		}
+		return nil
+	case c == ^byte(typeCodeByteString):
+		return nil
+	case c == typeCodeNested:
+		return validateNested(v[1 : len(v)-1])
+	case c > typeCodeTime:
+		// The descending order encoding of the other types is the bitwise
+		// inversion of the ascending one.
+		var buf [2 + maxBigIntBytes]byte
+		for i, d := range v {
+			buf[i] = ^d
+		}
+		return validateValue(buf[:len(v)])
+	case c == typeCodeNegativeBigInt || c == typeCodePositiveBigInt:
+		return validateBigInt(c, v[1:])
+	case typeCodeNegativeInt64 <= c && c <= typeCodePositiveInt64:
+		return validateInt(c, v[1:])
+	case c == typeCodeTime:
+		_, _, err := takeTimeValue(v[1:])
+		return err
+	default:
+		return nil
+	}
+}
+
+// validEscapedUTF8 reports whether the value escaped by AppendString in src,
+// which does not include the terminator, is valid UTF-8.
+func validEscapedUTF8(src []byte) bool {
+	// 0x00 never appears in a multibyte sequence, so it is enough to
+	// validate the segments between the escaped 0x00 bytes.
+	for {
+		i := bytes.IndexByte(src, '\x00')
+		if i == -1 {
+			return utf8.Valid(src)
+		}
+		if !utf8.Valid(src[:i]) {
+			return false
+		}
+		src = src[i+2:]
+	}
+}
+
+func validateNested(src []byte) error {
+	for len(src) > 0 {
+		if src[0] == typeCodeNull {
+			src = src[2:]
+			continue
+		}
+		n, err := validValueLen(src)
+		if err != nil {
+			return err
+		}
+		src = src[n:]
+	}
+	return nil
+}
+
+// validateInt validates the payload p of an integer with the type code c.
+func validateInt(c byte, p []byte) error {
+	if c == typeCodeIntZero {
+		return nil
+	}
+	var u uint64
+	for _, d := range p {
+		u = u<<8 | uint64(d)
+	}
+	if c < typeCodeIntZero {
+		u = ^u
+		if len(p) < 8 {
+			u &= 1<<(8*uint(len(p))) - 1
+		}
+	}
+	if u == 0 {
+		return ErrNonCanonical
+	}
+	switch c {
+	case typeCodeNegativeInt32:
+		// The fixed size form of AppendInt32, which never writes a magnitude
+		// greater than that of math.MinInt32.
+		if u > 1<<31 {
+			return ErrNonCanonical
+		}
+		return nil
+	case typeCodePositiveInt32, typeCodeNegativeInt64, typeCodePositiveInt64:
+		// The fixed size forms of AppendInt32, AppendUint32, AppendInt64,
+		// and AppendUint64.
+		return nil
+	}
+	if varIntPayloadLen(u) != len(p) {
+		return ErrNonCanonical
+	}
+	return nil
+}
+
+// validateBigInt validates the length byte and the magnitude p of a big
+// integer with the type code c.
+func validateBigInt(c byte, p []byte) error {
+	n, first := int(p[0]), p[1]
+	if c == typeCodeNegativeBigInt {
+		n, first = int(^p[0]), ^p[1]
+	}
+	if n <= 8 || first == 0 {
+		return ErrNonCanonical
+	}
+	return nil
+}
+
+// TakeInt32Strict takes an int32 value from b and returns it and the rest
+// of b like TakeInt32, but it returns an error wrapping ErrNonCanonical if
+// the value is not encoded in the form which AppendInt32 produces.
+func TakeInt32Strict(b []byte) (value int32, rest []byte, err error) {
+	value, rest, err = TakeInt32(b)
+	if err != nil {
+		return 0, b, err
+	}
+	var buf [5]byte
+	if !bytes.Equal(AppendInt32(buf[:0], value), b[:len(b)-len(rest)]) {
+		return 0, b, newDecodeError(b, ErrNonCanonical, expectedInt32)
+	}
+	return value, rest, nil
+}
+
+// TakeInt64Strict takes an int64 value from b and returns it and the rest
+// of b like TakeInt64, but it returns an error wrapping ErrNonCanonical if
+// the value is not encoded in the form which AppendInt64 produces.
+func TakeInt64Strict(b []byte) (value int64, rest []byte, err error) {
+	value, rest, err = TakeInt64(b)
+	if err != nil {
+		return 0, b, err
+	}
+	var buf [9]byte
+	if !bytes.Equal(AppendInt64(buf[:0], value), b[:len(b)-len(rest)]) {
+		return 0, b, newDecodeError(b, ErrNonCanonical, expectedInt64)
+	}
+	return value, rest, nil
+}
+
+// TakeUint32Strict takes an uint32 value from b and returns it and the rest
+// of b like TakeUint32, but it returns an error wrapping ErrNonCanonical if
+// the value is not encoded in the form which AppendUint32 produces.
+func TakeUint32Strict(b []byte) (value uint32, rest []byte, err error) {
+	value, rest, err = TakeUint32(b)
+	if err != nil {
+		return 0, b, err
+	}
+	var buf [5]byte
+	if !bytes.Equal(AppendUint32(buf[:0], value), b[:len(b)-len(rest)]) {
+		return 0, b, newDecodeError(b, ErrNonCanonical, expectedInt32)
+	}
+	return value, rest, nil
+}
+
+// TakeUint64Strict takes an uint64 value from b and returns it and the rest
+// of b like TakeUint64, but it returns an error wrapping ErrNonCanonical if
+// the value is not encoded in the form which AppendUint64 produces.
+func TakeUint64Strict(b []byte) (value uint64, rest []byte, err error) {
+	value, rest, err = TakeUint64(b)
+	if err != nil {
+		return 0, b, err
+	}
+	var buf [9]byte
+	if !bytes.Equal(AppendUint64(buf[:0], value), b[:len(b)-len(rest)]) {
+		return 0, b, newDecodeError(b, ErrNonCanonical, expectedInt64)
+	}
+	return value, rest, nil
+}
+
+// TakeVarIntStrict takes an int64 value from b and returns it and the rest
+// of b like TakeVarInt, but it returns an error wrapping ErrNonCanonical if
+// the value is not encoded in the form which AppendVarInt produces.
+func TakeVarIntStrict(b []byte) (value int64, rest []byte, err error) {
+	value, rest, err = TakeVarInt(b)
+	if err != nil {
+		return 0, b, err
+	}
+	var buf [9]byte
+	if !bytes.Equal(AppendVarInt(buf[:0], value), b[:len(b)-len(rest)]) {
+		return 0, b, newDecodeError(b, ErrNonCanonical, expectedVarInt)
+	}
+	return value, rest, nil
+}
+
+// TakeBigIntStrict takes a *big.Int value from b and returns it and the rest
+// of b like TakeBigInt, but it returns an error wrapping ErrNonCanonical if
+// the value is not encoded in the form which AppendBigInt produces.
+func TakeBigIntStrict(b []byte) (value *big.Int, rest []byte, err error) {
+	value, rest, err = TakeBigInt(b)
+	if err != nil {
+		return nil, b, err
+	}
+	if !bytes.Equal(AppendBigInt(nil, value), b[:len(b)-len(rest)]) {
+		return nil, b, newDecodeError(b, ErrNonCanonical, expectedBigInt)
+	}
+	return value, rest, nil
+}
+
+// TakeStringStrict takes a string value from b and returns it and the rest
+// of b like TakeString, but it returns an error wrapping ErrInvalidUTF8 if
+// the value is not valid UTF-8.
+func TakeStringStrict(b []byte) (value string, rest []byte, err error) {
+	value, rest, err = TakeString(b)
+	if err != nil {
+		return "", b, err
+	}
+	if !utf8.ValidString(value) {
+		return "", b, newDecodeError(b, ErrInvalidUTF8, expectedString)
+	}
+	return value, rest, nil
+}
@@ -0,0 +1,203 @@
+package sortedbytes_test
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"io"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/hnakamur/sortedbytes"
+)
+
+func TestValidate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		var b []byte
+		b = sortedbytes.AppendString(b, "FÔO\u0000bar")
+		b = sortedbytes.AppendStringDesc(b, "FÔO\u0000bar")
+		b = sortedbytes.AppendBytes(b, []byte("\x00\xff"))
+		b = sortedbytes.AppendBytesDesc(b, []byte("\x00\xff"))
+		for _, v := range []int64{0, 1, -1, 0xff, -0x100, 0xffffffff, -0x100000000, math.MaxInt64, math.MinInt64} {
+			b = sortedbytes.AppendVarInt(b, v)
+			b = sortedbytes.AppendVarIntDesc(b, v)
+			b = sortedbytes.AppendInt64(b, v)
+			b = sortedbytes.AppendInt64Desc(b, v)
+			b = sortedbytes.AppendInt32(b, int32(v))
+			b = sortedbytes.AppendInt32Desc(b, int32(v))
+		}
+		b = sortedbytes.AppendUint64(b, math.MaxUint64)
+		b = sortedbytes.AppendBigInt(b, new(big.Int).Neg(new(big.Int).SetUint64(math.MaxUint64)))
+		b = sortedbytes.AppendBigInt(b, mustParseBigInt("0x10000000000000000"))
+		b = sortedbytes.AppendBigInt(b, mustParseBigInt("-0x10000000000000000"))
+		b = sortedbytes.AppendBigIntDesc(b, mustParseBigInt("0x10000000000000000"))
+		b = sortedbytes.AppendBigIntDesc(b, mustParseBigInt("-0x10000000000000000"))
+		b = sortedbytes.AppendNullFloat64(b, sql.NullFloat64{})
+		b = sortedbytes.AppendFloat64(b, math.Copysign(0, -1))
+		b = sortedbytes.AppendFloat32Desc(b, float32(math.NaN()))
+		b = sortedbytes.AppendBool(b, true)
+		b = sortedbytes.AppendTime(b, time.Unix(1, 999999999))
+		b = sortedbytes.AppendTimeDesc(b, time.Unix(-1, 999999999))
+		b = sortedbytes.AppendUUID(b, sortedbytes.UUID{1})
+		b = sortedbytes.AppendVersionstamp(b, sortedbytes.IncompleteVersionstamp(1))
+		b = sortedbytes.AppendNested(b, sortedbytes.Tuple{nil, "a\x00", 1, sortedbytes.Tuple{nil}})
+		if err := sortedbytes.Validate(b); err != nil {
+			t.Errorf("got error: %s", err)
+		}
+		if err := sortedbytes.Validate(nil); err != nil {
+			t.Errorf("got error for empty input: %s", err)
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		testCases := []struct {
+			input []byte
+			err   error
+		}{
+			{input: []byte("\x02\xff\x00"), err: sortedbytes.ErrInvalidUTF8},
+			{input: []byte("\x02a\x00\xff\xc3\x00"), err: sortedbytes.ErrInvalidUTF8},
+			{input: sortedbytes.AppendStringDesc(nil, "\xc3"), err: sortedbytes.ErrInvalidUTF8},
+			{input: []byte("\xfd\xff\x01\xff\xff"), err: sortedbytes.ErrInvalidEscape},
+			{input: []byte("\x19\x00\x00\x00\x00"), err: sortedbytes.ErrNonCanonical},
+			{input: []byte("\x0f\xff\xff\xff\xff"), err: sortedbytes.ErrNonCanonical},
+			{input: []byte("\x0f\x00\x00\x00\x00"), err: sortedbytes.ErrNonCanonical},
+			{input: []byte("\x0f\x7f\xff\xff\xfe"), err: sortedbytes.ErrNonCanonical},
+			{input: []byte("\x0f\x7f\xff\xff\xff"), err: nil},
+			{input: []byte("\xf0\xff\xff\xff\xff"), err: sortedbytes.ErrNonCanonical},
+			{input: []byte("\x1c\x00\x00\x00\x00\x00\x00\x00\x00"), err: sortedbytes.ErrNonCanonical},
+			{input: []byte("\x15\x00"), err: sortedbytes.ErrNonCanonical},
+			{input: []byte("\x13\xff"), err: sortedbytes.ErrNonCanonical},
+			{input: []byte("\x16\x00\x01"), err: sortedbytes.ErrNonCanonical},
+			{input: []byte("\x18\x00\x00\x00\x01"), err: sortedbytes.ErrNonCanonical},
+			{input: []byte("\x1a\x00\x00\x00\x00\x00\x01"), err: sortedbytes.ErrNonCanonical},
+			{input: []byte("\x1a\x00\x01\x00\x00\x00\x00"), err: nil},
+			{input: []byte("\xea\xff"), err: sortedbytes.ErrNonCanonical},
+			{input: []byte("\x1d\x08\x01\x00\x00\x00\x00\x00\x00\x00"), err: sortedbytes.ErrNonCanonical},
+			{input: []byte("\x1d\x09\x00\x01\x00\x00\x00\x00\x00\x00\x00"), err: sortedbytes.ErrNonCanonical},
+			{input: []byte("\x0b\xf6\xff\xfe\xff\xff\xff\xff\xff\xff\xff"), err: sortedbytes.ErrNonCanonical},
+			{input: []byte("\xe2\xf6\xff\xfe\xff\xff\xff\xff\xff\xff\xff"), err: sortedbytes.ErrNonCanonical},
+			{input: []byte("\x40\x80\x00\x00\x00\x00\x00\x00\x00\x3b\x9a\xca\x00"), err: sortedbytes.ErrValueOutOfRange},
+			{input: []byte("\x05\x15\x00\x00"), err: sortedbytes.ErrNonCanonical},
+			{input: []byte("\x05\x02\xc3\x00\x00"), err: sortedbytes.ErrInvalidUTF8},
+			{input: []byte("\x03"), err: sortedbytes.ErrUnexpectedTypeCode},
+			{input: []byte("\x1c\x00"), err: io.ErrUnexpectedEOF},
+		}
+		for i, tc := range testCases {
+			b := append([]byte("\x14\x26"), tc.input...)
+			err := sortedbytes.Validate(b)
+			if tc.err == nil {
+				if err != nil {
+					t.Errorf("case %d: got error: %s", i, err)
+				}
+				continue
+			}
+			if !errors.Is(err, tc.err) {
+				t.Errorf("case %d: error unmatch: got=%v, want=%v", i, err, tc.err)
+				continue
+			}
+			var de *sortedbytes.DecodeError
+			if !errors.As(err, &de) {
+				t.Errorf("case %d: got error %v, want *DecodeError", i, err)
+				continue
+			}
+			wantOffset := 2
+			if tc.err == io.ErrUnexpectedEOF {
+				wantOffset = len(b)
+			}
+			if got, want := de.Offset, wantOffset; got != want {
+				t.Errorf("case %d: offset unmatch: got=%d, want=%d", i, got, want)
+			}
+		}
+	})
+}
+
+func TestTakeStrict(t *testing.T) {
+	t.Run("roundtrip", func(t *testing.T) {
+		for i, v := range []int64{0, 1, -1, 0x100, math.MaxInt32, math.MinInt32, math.MaxInt64, math.MinInt64} {
+			if got, _, err := sortedbytes.TakeInt32Strict(sortedbytes.AppendInt32(nil, int32(v))); err != nil || got != int32(v) {
+				t.Errorf("case %d: TakeInt32Strict: got=%d, err=%v, want=%d", i, got, err, int32(v))
+			}
+			if got, _, err := sortedbytes.TakeInt64Strict(sortedbytes.AppendInt64(nil, v)); err != nil || got != v {
+				t.Errorf("case %d: TakeInt64Strict: got=%d, err=%v, want=%d", i, got, err, v)
+			}
+			if got, _, err := sortedbytes.TakeUint32Strict(sortedbytes.AppendUint32(nil, uint32(v))); err != nil || got != uint32(v) {
+				t.Errorf("case %d: TakeUint32Strict: got=%d, err=%v, want=%d", i, got, err, uint32(v))
+			}
+			if got, _, err := sortedbytes.TakeUint64Strict(sortedbytes.AppendUint64(nil, uint64(v))); err != nil || got != uint64(v) {
+				t.Errorf("case %d: TakeUint64Strict: got=%d, err=%v, want=%d", i, got, err, uint64(v))
+			}
+			if got, _, err := sortedbytes.TakeVarIntStrict(sortedbytes.AppendVarInt(nil, v)); err != nil || got != v {
+				t.Errorf("case %d: TakeVarIntStrict: got=%d, err=%v, want=%d", i, got, err, v)
+			}
+			if got, _, err := sortedbytes.TakeBigIntStrict(sortedbytes.AppendBigInt(nil, big.NewInt(v))); err != nil || got.Int64() != v {
+				t.Errorf("case %d: TakeBigIntStrict: got=%s, err=%v, want=%d", i, got, err, v)
+			}
+		}
+		big := mustParseBigInt("-0x10000000000000000")
+		if got, _, err := sortedbytes.TakeBigIntStrict(sortedbytes.AppendBigInt(nil, big)); err != nil || got.Cmp(big) != 0 {
+			t.Errorf("TakeBigIntStrict: got=%s, err=%v, want=%s", got, err, big)
+		}
+		s := "FÔO\u0000bar"
+		if got, _, err := sortedbytes.TakeStringStrict(sortedbytes.AppendString(nil, s)); err != nil || got != s {
+			t.Errorf("TakeStringStrict: got=%q, err=%v, want=%q", got, err, s)
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		testCases := []struct {
+			take  func([]byte) (interface{}, []byte, error)
+			input []byte
+			err   error
+		}{
+			{take: takeInt32Strict, input: []byte("\x19\x00\x00\x00\x00"), err: sortedbytes.ErrNonCanonical},
+			{take: takeInt32Strict, input: []byte("\x0f\xff\xff\xff\xff"), err: sortedbytes.ErrNonCanonical},
+			{take: takeInt32Strict, input: []byte("\x15\x01"), err: sortedbytes.ErrUnexpectedTypeCode},
+			{take: takeInt64Strict, input: []byte("\x1c\x00\x00\x00\x00\x00\x00\x00\x00"), err: sortedbytes.ErrNonCanonical},
+			{take: takeUint32Strict, input: []byte("\x19\x00\x00\x00\x00"), err: sortedbytes.ErrNonCanonical},
+			{take: takeUint64Strict, input: []byte("\x1c\x00\x00\x00\x00\x00\x00\x00\x00"), err: sortedbytes.ErrNonCanonical},
+			{take: takeVarIntStrict, input: []byte("\x16\x00\x01"), err: sortedbytes.ErrNonCanonical},
+			{take: takeVarIntStrict, input: []byte("\x19\x00\x00\x00\x01"), err: sortedbytes.ErrNonCanonical},
+			{take: takeVarIntStrict, input: []byte("\x1c\x00\x00\x00\x00\x00\x00\x00\x01"), err: sortedbytes.ErrNonCanonical},
+			{take: takeBigIntStrict, input: []byte("\x15\x01"), err: sortedbytes.ErrNonCanonical},
+			{take: takeBigIntStrict, input: []byte("\x1d\x09\x00\x01\x00\x00\x00\x00\x00\x00\x00"), err: sortedbytes.ErrNonCanonical},
+			{take: takeStringStrict, input: []byte("\x02\xc3\x28\x00"), err: sortedbytes.ErrInvalidUTF8},
+		}
+		for i, tc := range testCases {
+			_, rest, err := tc.take(tc.input)
+			if !errors.Is(err, tc.err) {
+				t.Errorf("case %d: error unmatch: got=%v, want=%v", i, err, tc.err)
+			}
+			if !bytes.Equal(rest, tc.input) {
+				t.Errorf("case %d: rest unmatch: got=0x%x, want=0x%x", i, rest, tc.input)
+			}
+		}
+	})
+}
+
+func takeBigIntStrict(b []byte) (interface{}, []byte, error) {
+	return sortedbytes.TakeBigIntStrict(b)
+}
+
+func takeInt32Strict(b []byte) (interface{}, []byte, error) {
+	return sortedbytes.TakeInt32Strict(b)
+}
+
+func takeInt64Strict(b []byte) (interface{}, []byte, error) {
+	return sortedbytes.TakeInt64Strict(b)
+}
+
+func takeStringStrict(b []byte) (interface{}, []byte, error) {
+	return sortedbytes.TakeStringStrict(b)
+}
+
+func takeUint32Strict(b []byte) (interface{}, []byte, error) {
+	return sortedbytes.TakeUint32Strict(b)
+}
+
+func takeUint64Strict(b []byte) (interface{}, []byte, error) {
+	return sortedbytes.TakeUint64Strict(b)
+}
+
+func takeVarIntStrict(b []byte) (interface{}, []byte, error) {
+	return sortedbytes.TakeVarIntStrict(b)
+}
@@ -0,0 +1,89 @@
+package sortedbytes
+
+import "bytes"
+
+// Subspace is a namespace of keys which start with the same prefix, like
+// the subspace of the FDB tuple layer.
+type Subspace struct {
+	prefix []byte
+}
+
+// NewSubspace returns a Subspace whose keys start with prefix.
+//
+// prefix is usually built with Append functions or Tuple.Pack, for example
+// the application name and the table ID. NewSubspace copies prefix, so
+// the caller can modify it afterwards.
+func NewSubspace(prefix []byte) Subspace {
+	return Subspace{prefix: append([]byte(nil), prefix...)}
+}
+
+// Bytes returns the prefix of s. The caller must not modify the result.
+func (s Subspace) Bytes() []byte {
+	return s.prefix
+}
+
+// Sub returns the child subspace of s whose prefix is the prefix of s
+// followed by elements packed as a Tuple.
+//
+// Sub panics if elements contain an element of an unsupported type.
+func (s Subspace) Sub(elements ...interface{}) Subspace {
+	return Subspace{prefix: appendTuple(s.newKey(Tuple(elements)), elements)}
+}
+
+// Pack returns the prefix of s followed by the encoded bytes of t.
+//
+// Pack panics if t contains an element of an unsupported type.
+func (s Subspace) Pack(t Tuple) []byte {
+	return appendTuple(s.newKey(t), t)
+}
+
+// PackWithVersionstamp returns the prefix of s followed by the encoded bytes
+// of t, and the offset of the placeholder of the incomplete versionstamp
+// in them like Tuple.PackWithVersionstamp.
+func (s Subspace) PackWithVersionstamp(t Tuple) (b []byte, offset int, err error) {
+	offset = -1
+	b, err = appendTupleWithVersionstamp(s.newKey(t), t, false, &offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	if offset == -1 {
+		return nil, 0, ErrNoIncompleteVersionstamp
+	}
+	return b, offset, nil
+}
+
+// Unpack strips the prefix of s from key and decodes the rest into a Tuple.
+//
+// It returns ErrKeyNotInSubspace if key does not start with the prefix of s.
+// Otherwise the returned error is a *DecodeError whose Offset is the offset
+// in key.
+func (s Subspace) Unpack(key []byte) (Tuple, error) {
+	if !s.Contains(key) {
+		return nil, ErrKeyNotInSubspace
+	}
+	t, err := Unpack(key[len(s.prefix):])
+	if err != nil {
+		return nil, AddOffset(err, len(s.prefix))
+	}
+	return t, nil
+}
+
+// Contains reports whether key starts with the prefix of s.
+func (s Subspace) Contains(key []byte) bool {
+	return bytes.HasPrefix(key, s.prefix)
+}
+
+// Range returns the range of all keys in s except the prefix itself.
+//
+// It is the same as ComponentRange with the unbounded bounds, so the range
+// contains exactly the keys which start with the prefix of s followed by
+// one or more encoded values.
+func (s Subspace) Range() KeyRange {
+	return ComponentRange(s.prefix, Unbounded(), Unbounded())
+}
+
+// newKey returns a copy of the prefix of s with the room for t.
+func (s Subspace) newKey(t Tuple) []byte {
+	key := make([]byte, 0, len(s.prefix)+t.EncodedLen())
+	return append(key, s.prefix...)
+}
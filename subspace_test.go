@@ -0,0 +1,119 @@
+package sortedbytes_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/hnakamur/sortedbytes"
+)
+
+func TestSubspace(t *testing.T) {
+	prefix := sortedbytes.AppendInt32(sortedbytes.AppendString(nil, "app"), 7)
+	s := sortedbytes.NewSubspace(prefix)
+	prefix[0] = 0
+	if got, want := s.Bytes(), sortedbytes.AppendInt32(sortedbytes.AppendString(nil, "app"), 7); !bytes.Equal(got, want) {
+		t.Fatalf("prefix unmatch: got=0x%x, want=0x%x", got, want)
+	}
+
+	t.Run("roundtrip", func(t *testing.T) {
+		testCases := []sortedbytes.Tuple{
+			{},
+			{"user", int64(1)},
+			{nil, []byte("\x00"), sortedbytes.Tuple{int64(-1)}},
+		}
+		for i, tc := range testCases {
+			key := s.Pack(tc)
+			if got, want := key, append(s.Bytes()[:len(s.Bytes()):len(s.Bytes())], tc.Pack()...); !bytes.Equal(got, want) {
+				t.Errorf("case %d: key unmatch: got=0x%x, want=0x%x", i, got, want)
+			}
+			if !s.Contains(key) {
+				t.Errorf("case %d: key not contained", i)
+			}
+			got, err := s.Unpack(key)
+			if err != nil {
+				t.Errorf("case %d: got error: %s", i, err)
+				continue
+			}
+			if len(tc) == 0 {
+				tc = nil
+			}
+			if !reflect.DeepEqual(got, tc) {
+				t.Errorf("case %d: tuple unmatch: got=%v, want=%v", i, got, tc)
+			}
+		}
+	})
+	t.Run("sub", func(t *testing.T) {
+		sub := s.Sub("users", 1)
+		if got, want := sub.Bytes(), s.Pack(sortedbytes.Tuple{"users", 1}); !bytes.Equal(got, want) {
+			t.Errorf("prefix unmatch: got=0x%x, want=0x%x", got, want)
+		}
+		key := sub.Pack(sortedbytes.Tuple{"alice"})
+		if !s.Contains(key) || !sub.Contains(key) {
+			t.Errorf("key not contained")
+		}
+		got, err := s.Unpack(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := (sortedbytes.Tuple{"users", int64(1), "alice"}); !reflect.DeepEqual(got, want) {
+			t.Errorf("tuple unmatch: got=%v, want=%v", got, want)
+		}
+		if sub.Contains(s.Pack(sortedbytes.Tuple{"users", 2})) {
+			t.Errorf("key of sibling contained")
+		}
+	})
+	t.Run("range", func(t *testing.T) {
+		r := s.Range()
+		testCases := []struct {
+			key  []byte
+			want bool
+		}{
+			{key: s.Bytes(), want: false},
+			{key: s.Pack(sortedbytes.Tuple{nil}), want: true},
+			{key: s.Pack(sortedbytes.Tuple{"user", 1}), want: true},
+			{key: s.Sub("users").Pack(sortedbytes.Tuple{sortedbytes.Tuple{}}), want: true},
+			{key: sortedbytes.AppendInt32(sortedbytes.AppendString(nil, "app"), 8), want: false},
+			{key: sortedbytes.AppendString(nil, "app"), want: false},
+		}
+		for i, tc := range testCases {
+			if got := r.Contains(tc.key); got != tc.want {
+				t.Errorf("case %d: contains unmatch: got=%v, want=%v, key=0x%x", i, got, tc.want, tc.key)
+			}
+		}
+	})
+	t.Run("versionstamp", func(t *testing.T) {
+		key, offset, err := s.PackWithVersionstamp(sortedbytes.Tuple{"log", sortedbytes.IncompleteVersionstamp(1)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, tupleOffset, err := sortedbytes.Tuple{"log", sortedbytes.IncompleteVersionstamp(1)}.PackWithVersionstamp()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := offset, len(s.Bytes())+tupleOffset; got != want {
+			t.Errorf("offset unmatch: got=%d, want=%d", got, want)
+		}
+		if got, want := key[offset:offset+10], bytes.Repeat([]byte{0xff}, 10); !bytes.Equal(got, want) {
+			t.Errorf("placeholder unmatch: got=0x%x, want=0x%x", got, want)
+		}
+		if _, _, err := s.PackWithVersionstamp(sortedbytes.Tuple{"log"}); !errors.Is(err, sortedbytes.ErrNoIncompleteVersionstamp) {
+			t.Errorf("error unmatch: got=%v, want=%v", err, sortedbytes.ErrNoIncompleteVersionstamp)
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		if _, err := s.Unpack(sortedbytes.AppendString(nil, "other")); !errors.Is(err, sortedbytes.ErrKeyNotInSubspace) {
+			t.Errorf("error unmatch: got=%v, want=%v", err, sortedbytes.ErrKeyNotInSubspace)
+		}
+		key := append(s.Pack(sortedbytes.Tuple{"user"}), 0x03)
+		_, err := s.Unpack(key)
+		var de *sortedbytes.DecodeError
+		if !errors.As(err, &de) {
+			t.Fatalf("got error %v, want *DecodeError", err)
+		}
+		if got, want := de.Offset, len(key)-1; got != want {
+			t.Errorf("offset unmatch: got=%d, want=%d", got, want)
+		}
+	})
+}
@@ -0,0 +1,92 @@
+package sortedbytes
+
+import (
+	"database/sql"
+	"encoding/binary"
+	"io"
+	"time"
+)
+
+const nanosecondsPerSecond = 1_000_000_000
+
+// AppendNullTime appends a sql.NullTime value to dst.
+//
+// You need to store the result of AppendNullTime like:
+//     dst = sortedbytes.AppendNullTime(dst, value)
+func AppendNullTime(dst []byte, value sql.NullTime) []byte {
+	if value.Valid {
+		return AppendTime(dst, value.Time)
+	}
+	return append(dst, typeCodeNull)
+}
+
+// AppendTime appends a time.Time value to dst.
+//
+// The value is encoded as the type code 0x40, which is the first
+// user type code in the FDB tuple layer, followed by the seconds since
+// the Unix epoch in 8 bytes with the sign bit flipped and the nanoseconds
+// within the second in 4 bytes. Both are in big endian, so encoded values
+// keep the order of instants in time.
+//
+// Only the instant is encoded. The location and the monotonic clock
+// reading are dropped, so two values which represent the same instant in
+// different time zones are encoded to the same bytes.
+//
+// You need to store the result of AppendTime like:
+//     dst = sortedbytes.AppendTime(dst, value)
+func AppendTime(dst []byte, value time.Time) []byte {
+	var b [12]byte
+	binary.BigEndian.PutUint64(b[:8], uint64(value.Unix())^0x8000_0000_0000_0000)
+	binary.BigEndian.PutUint32(b[8:], uint32(value.Nanosecond()))
+	return append(append(dst, typeCodeTime), b[:]...)
+}
+
+// TakeNullTime takes a sql.NullTime value from b and returns it and the rest of b.
+//
+// The returned time is in UTC.
+func TakeNullTime(b []byte) (value sql.NullTime, rest []byte, err error) {
+	var c byte
+	c, rest, err = takeTypeCode(b)
+	if err != nil {
+		return value, b, newNullDecodeError(b, err, expectedTime)
+	}
+	if c == typeCodeNull {
+		return value, b[1:], nil
+	}
+	if c != typeCodeTime {
+		return value, b, newNullDecodeError(b, ErrUnexpectedTypeCode, expectedTime)
+	}
+	var v time.Time
+	v, rest, err = takeTimeValue(rest)
+	if err != nil {
+		return value, b, newNullDecodeError(b, err, expectedTime)
+	}
+	return sql.NullTime{Valid: true, Time: v}, rest, nil
+}
+
+// TakeTime takes a time.Time value from b and returns it and the rest of b.
+//
+// The returned time is in UTC.
+func TakeTime(b []byte) (value time.Time, rest []byte, err error) {
+	rest, err = expectTypeCode(b, typeCodeTime)
+	if err != nil {
+		return time.Time{}, b, newDecodeError(b, err, expectedTime)
+	}
+	value, rest, err = takeTimeValue(rest)
+	if err != nil {
+		return time.Time{}, b, newDecodeError(b, err, expectedTime)
+	}
+	return value, rest, nil
+}
+
+func takeTimeValue(b []byte) (value time.Time, rest []byte, err error) {
+	if len(b) < 12 {
+		return time.Time{}, nil, io.ErrUnexpectedEOF
+	}
+	sec := int64(binary.BigEndian.Uint64(b[:8]) ^ 0x8000_0000_0000_0000)
+	nsec := binary.BigEndian.Uint32(b[8:12])
+	if nsec >= nanosecondsPerSecond {
+		return time.Time{}, nil, ErrValueOutOfRange
+	}
+	return time.Unix(sec, int64(nsec)).UTC(), b[12:], nil
+}
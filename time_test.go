@@ -0,0 +1,163 @@
+package sortedbytes_test
+
+import (
+	"bytes"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/hnakamur/sortedbytes"
+)
+
+func TestAppendNullTime(t *testing.T) {
+	t.Run("order", func(t *testing.T) {
+		testCases := []struct {
+			a, b sql.NullTime
+		}{
+			{
+				a: sql.NullTime{Valid: false, Time: time.Time{}},
+				b: sql.NullTime{Valid: true, Time: time.Time{}},
+			},
+			{
+				a: sql.NullTime{Valid: true, Time: time.Unix(-1, 0)},
+				b: sql.NullTime{Valid: true, Time: time.Unix(0, 0)},
+			},
+			{
+				a: sql.NullTime{Valid: true, Time: time.Unix(0, 0)},
+				b: sql.NullTime{Valid: true, Time: time.Unix(0, 1)},
+			},
+		}
+		for i, tc := range testCases {
+			a := sortedbytes.AppendNullTime([]byte(nil), tc.a)
+			b := sortedbytes.AppendNullTime([]byte(nil), tc.b)
+			if got, want := bytes.Compare(a, b), -1; got != want {
+				t.Errorf("case %d: compare result unmatch: got=%d, want=%d, a=0x%x, b=0x%x",
+					i, got, want, a, b)
+			}
+		}
+	})
+}
+
+func TestTakeNullTime(t *testing.T) {
+	t.Run("roundtrip", func(t *testing.T) {
+		testCases := []sql.NullTime{
+			{Valid: false, Time: time.Time{}},
+			{Valid: true, Time: time.Time{}},
+			{Valid: true, Time: time.Date(2020, 5, 17, 12, 34, 56, 789, time.UTC)},
+		}
+		for i, input := range testCases {
+			b := sortedbytes.AppendNullTime([]byte(nil), input)
+			v, rest, err := sortedbytes.TakeNullTime(b)
+			if err != nil {
+				t.Errorf("case %d: got error: %s", i, err)
+			}
+			if got, want := v, input; got.Valid != want.Valid || !got.Time.Equal(want.Time) {
+				t.Errorf("case %d: value unmatch: got=%+v, want=%+v", i, got, want)
+			}
+			if got, want := len(rest), 0; got != want {
+				t.Errorf("case %d: rest length unmatch: got=%d, want=%d", i, got, want)
+			}
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		testCases := [][]byte{
+			[]byte("\x40"),
+			[]byte("\x40\x80\x00\x00\x00\x00\x00\x00\x00\x3b\x9a\xca\x00"),
+			[]byte("\x02"),
+		}
+		for i, input := range testCases {
+			_, _, err := sortedbytes.TakeNullTime(input)
+			if err == nil {
+				t.Errorf("case %d: got no error", i)
+			}
+		}
+	})
+}
+
+func TestAppendTime(t *testing.T) {
+	t.Run("order", func(t *testing.T) {
+		testCases := []time.Time{
+			time.Time{},
+			time.Date(1, 1, 1, 0, 0, 0, 1, time.UTC),
+			time.Date(1969, 12, 31, 23, 59, 59, 999_999_999, time.UTC),
+			time.Unix(0, 0),
+			time.Unix(0, 1),
+			time.Unix(1, 0),
+			time.Unix(1<<32, 0),
+			time.Date(2262, 4, 12, 0, 0, 0, 0, time.UTC),
+			time.Date(9999, 12, 31, 23, 59, 59, 999_999_999, time.UTC),
+		}
+		for i := 1; i < len(testCases); i++ {
+			a := sortedbytes.AppendTime([]byte(nil), testCases[i-1])
+			b := sortedbytes.AppendTime([]byte(nil), testCases[i])
+			if got, want := bytes.Compare(a, b), -1; got != want {
+				t.Errorf("case %d: compare result unmatch: got=%d, want=%d, a=0x%x, b=0x%x",
+					i, got, want, a, b)
+			}
+		}
+	})
+	t.Run("location", func(t *testing.T) {
+		utc := time.Date(2020, 5, 17, 3, 0, 0, 0, time.UTC)
+		jst := utc.In(time.FixedZone("JST", 9*60*60))
+		a := sortedbytes.AppendTime([]byte(nil), utc)
+		b := sortedbytes.AppendTime([]byte(nil), jst)
+		if !bytes.Equal(a, b) {
+			t.Errorf("encoded bytes unmatch: utc=0x%x, jst=0x%x", a, b)
+		}
+	})
+}
+
+func TestTakeTime(t *testing.T) {
+	t.Run("roundtrip", func(t *testing.T) {
+		testCases := []time.Time{
+			time.Time{},
+			time.Date(1, 1, 1, 0, 0, 0, 1, time.UTC),
+			time.Unix(-1, 999_999_999).UTC(),
+			time.Unix(0, 0).UTC(),
+			time.Date(2020, 5, 17, 12, 34, 56, 789, time.UTC),
+			time.Date(2262, 4, 12, 0, 0, 0, 0, time.UTC),
+			time.Date(9999, 12, 31, 23, 59, 59, 999_999_999, time.UTC),
+		}
+		for i, input := range testCases {
+			b := sortedbytes.AppendTime([]byte(nil), input)
+			v, rest, err := sortedbytes.TakeTime(b)
+			if err != nil {
+				t.Errorf("case %d: got error: %s", i, err)
+			}
+			if got, want := v, input; got != want {
+				t.Errorf("case %d: value unmatch: got=%v, want=%v", i, got, want)
+			}
+			if got, want := len(rest), 0; got != want {
+				t.Errorf("case %d: rest length unmatch: got=%d, want=%d", i, got, want)
+			}
+		}
+	})
+	t.Run("utc", func(t *testing.T) {
+		input := time.Date(2020, 5, 17, 12, 0, 0, 0, time.FixedZone("JST", 9*60*60))
+		b := sortedbytes.AppendTime([]byte(nil), input)
+		v, _, err := sortedbytes.TakeTime(b)
+		if err != nil {
+			t.Fatalf("got error: %s", err)
+		}
+		if got, want := v.Location(), time.UTC; got != want {
+			t.Errorf("location unmatch: got=%v, want=%v", got, want)
+		}
+		if !v.Equal(input) {
+			t.Errorf("instant unmatch: got=%v, want=%v", v, input)
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		testCases := [][]byte{
+			[]byte("\x40"),
+			[]byte("\x40\x80\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00"),
+			[]byte("\x40\x80\x00\x00\x00\x00\x00\x00\x00\x3b\x9a\xca\x00"),
+			[]byte("\x02"),
+		}
+		for i, input := range testCases {
+			_, _, err := sortedbytes.TakeTime(input)
+			if err == nil {
+				t.Errorf("case %d: got no error", i)
+			}
+		}
+	})
+}
@@ -0,0 +1,279 @@
+package sortedbytes
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"time"
+)
+
+// Tuple is a list of values of heterogeneous types which can be packed
+// into a key and unpacked from a key without knowing the types of
+// the values in advance.
+//
+// Supported element types for Pack are nil, string, []byte, int, int8,
+// int16, int32, int64, uint, uint8, uint16, uint32, uint64, *big.Int,
+// float32, float64, bool, time.Time, UUID, Versionstamp, and Tuple.
+// A Tuple element is encoded as a nested tuple with AppendNested.
+//
+// Unpack returns elements of the types nil, string, []byte, int64, uint64,
+// *big.Int, float32, float64, bool, time.Time, UUID, Versionstamp, and
+// Tuple. An integer is returned as int64 if it fits in int64, as uint64
+// if it fits in uint64, and as *big.Int otherwise.
+type Tuple []interface{}
+
+// Pack returns the encoded bytes of t.
+//
+// Integers are encoded in the compact form of AppendVarInt, or with
+// AppendUint64 or AppendBigInt when they do not fit in int64, so the result
+// is compatible with the FDB tuple layer except for the limitations
+// of this package.
+//
+// Pack panics if t contains an element of an unsupported type.
+func (t Tuple) Pack() []byte {
+	return appendTuple(nil, t)
+}
+
+// PackWithVersionstamp returns the encoded bytes of t and the offset of
+// the placeholder of the incomplete versionstamp in them.
+//
+// t must contain exactly one incomplete versionstamp, which may be in
+// a nested tuple. The storage engine is expected to overwrite the 10 bytes
+// at offset with the transaction version at commit time.
+//
+// It returns ErrNoIncompleteVersionstamp if t contains no incomplete
+// versionstamp, and ErrMultipleIncompleteVersionstamps if t contains more
+// than one.
+//
+// PackWithVersionstamp panics if t contains an element of an unsupported
+// type.
+func (t Tuple) PackWithVersionstamp() (b []byte, offset int, err error) {
+	offset = -1
+	b, err = appendTupleWithVersionstamp(nil, t, false, &offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	if offset == -1 {
+		return nil, 0, ErrNoIncompleteVersionstamp
+	}
+	return b, offset, nil
+}
+
+// appendTupleWithVersionstamp appends t in the same way as appendTuple or
+// AppendNested without the type code and the terminator when nested is
+// true, and sets the offset of the placeholder of the incomplete
+// versionstamp to *offset.
+func appendTupleWithVersionstamp(dst []byte, t Tuple, nested bool, offset *int) ([]byte, error) {
+	for i, e := range t {
+		switch v := e.(type) {
+		case nil:
+			if nested {
+				dst = append(dst, typeCodeNull, '\xFF')
+			} else {
+				dst = append(dst, typeCodeNull)
+			}
+		case Versionstamp:
+			if !v.IsComplete() {
+				if *offset != -1 {
+					return nil, ErrMultipleIncompleteVersionstamps
+				}
+				*offset = len(dst) + 1
+			}
+			dst = AppendVersionstamp(dst, v)
+		case Tuple:
+			var err error
+			dst, err = appendTupleWithVersionstamp(append(dst, typeCodeNested), v, true, offset)
+			if err != nil {
+				return nil, err
+			}
+			dst = append(dst, typeCodeNull)
+		default:
+			dst = appendTupleElement(dst, i, e)
+		}
+	}
+	return dst, nil
+}
+
+func appendTuple(dst []byte, t Tuple) []byte {
+	for i, e := range t {
+		dst = appendTupleElement(dst, i, e)
+	}
+	return dst
+}
+
+func appendTupleElement(dst []byte, i int, e interface{}) []byte {
+	switch v := e.(type) {
+	case nil:
+		return append(dst, typeCodeNull)
+	case string:
+		return AppendString(dst, v)
+	case []byte:
+		return AppendBytes(dst, v)
+	case int:
+		return AppendVarInt(dst, int64(v))
+	case int8:
+		return AppendVarInt(dst, int64(v))
+	case int16:
+		return AppendVarInt(dst, int64(v))
+	case int32:
+		return AppendVarInt(dst, int64(v))
+	case int64:
+		return AppendVarInt(dst, v)
+	case uint:
+		return appendTupleUint(dst, uint64(v))
+	case uint8:
+		return appendTupleUint(dst, uint64(v))
+	case uint16:
+		return appendTupleUint(dst, uint64(v))
+	case uint32:
+		return appendTupleUint(dst, uint64(v))
+	case uint64:
+		return appendTupleUint(dst, v)
+	case *big.Int:
+		return AppendBigInt(dst, v)
+	case float32:
+		return AppendFloat32(dst, v)
+	case float64:
+		return AppendFloat64(dst, v)
+	case bool:
+		return AppendBool(dst, v)
+	case time.Time:
+		return AppendTime(dst, v)
+	case UUID:
+		return AppendUUID(dst, v)
+	case Versionstamp:
+		return AppendVersionstamp(dst, v)
+	case Tuple:
+		return AppendNested(dst, v)
+	default:
+		panic(fmt.Sprintf("sortedbytes: unsupported type %T for tuple element at index %d", e, i))
+	}
+}
+
+func appendTupleUint(dst []byte, v uint64) []byte {
+	if v > math.MaxInt64 {
+		return AppendUint64(dst, v)
+	}
+	return AppendVarInt(dst, int64(v))
+}
+
+// Unpack decodes b into a Tuple.
+// b must contain only the encoded elements of a tuple.
+//
+// The returned error is a *DecodeError whose Offset is the offset in b.
+func Unpack(b []byte) (Tuple, error) {
+	var t Tuple
+	rest := b
+	for len(rest) > 0 {
+		var e interface{}
+		var err error
+		e, rest, err = takeTupleElement(rest)
+		if err != nil {
+			return nil, AddOffset(err, len(b)-len(rest))
+		}
+		t = append(t, e)
+	}
+	return t, nil
+}
+
+var expectedTuple = append(append([]byte{typeCodeNull, typeCodeByteString, typeCodeUTF8String, typeCodeNested},
+	expectedBigInt...), typeCodeFloat32, typeCodeFloat64, typeCodeFalse, typeCodeTrue, typeCodeUUID, typeCodeVersionstamp, typeCodeTime)
+
+func takeTupleElement(b []byte) (value interface{}, rest []byte, err error) {
+	c := b[0]
+	switch {
+	case c == typeCodeNull:
+		return nil, b[1:], nil
+	case c == typeCodeByteString:
+		return TakeBytes(b)
+	case c == typeCodeUTF8String:
+		return TakeString(b)
+	case c == typeCodeNested:
+		return TakeNested(b)
+	case c == typeCodeNegativeBigInt || c == typeCodePositiveBigInt:
+		return TakeBigInt(b)
+	case typeCodeNegativeInt64 <= c && c <= typeCodePositiveInt64:
+		return takeTupleInt(b)
+	case c == typeCodeFloat32:
+		return TakeFloat32(b)
+	case c == typeCodeFloat64:
+		return TakeFloat64(b)
+	case c == typeCodeFalse || c == typeCodeTrue:
+		return TakeBool(b)
+	case c == typeCodeUUID:
+		return TakeUUID(b)
+	case c == typeCodeVersionstamp:
+		return TakeVersionstamp(b)
+	case c == typeCodeTime:
+		return TakeTime(b)
+	default:
+		return nil, b, newDecodeError(b, ErrUnexpectedTypeCode, expectedTuple)
+	}
+}
+
+func takeTupleInt(b []byte) (value interface{}, rest []byte, err error) {
+	v, rest, err := TakeVarInt(b)
+	if !errors.Is(err, ErrValueOutOfRange) {
+		return v, rest, err
+	}
+	if u, rest, err := TakeUint64(b); err == nil {
+		return u, rest, nil
+	}
+	return TakeBigInt(b)
+}
+
+// AppendNested appends a Tuple value to dst as a nested tuple.
+//
+// The elements are encoded in the same way as Pack except that a nil
+// element is encoded as 0x00 0xFF, and the nested tuple is terminated
+// with 0x00, so nested tuples sort element by element and a nested tuple
+// sorts before the longer ones which it is a prefix of.
+//
+// AppendNested panics if value contains an element of an unsupported type.
+//
+// You need to store the result of AppendNested like:
+//     dst = sortedbytes.AppendNested(dst, value)
+func AppendNested(dst []byte, value Tuple) []byte {
+	dst = append(dst, typeCodeNested)
+	for i, e := range value {
+		if e == nil {
+			dst = append(dst, typeCodeNull, '\xFF')
+			continue
+		}
+		dst = appendTupleElement(dst, i, e)
+	}
+	return append(dst, typeCodeNull)
+}
+
+// TakeNested takes a nested tuple from b and returns it and the rest of b.
+// An empty nested tuple is returned as an empty, non-nil Tuple.
+func TakeNested(b []byte) (value Tuple, rest []byte, err error) {
+	rest, err = expectTypeCode(b, typeCodeNested)
+	if err != nil {
+		return nil, b, newDecodeError(b, err, expectedNested)
+	}
+	value = Tuple{}
+	for {
+		if len(rest) == 0 {
+			return nil, b, newDecodeError(b, io.ErrUnexpectedEOF, expectedNested)
+		}
+		if rest[0] == typeCodeNull {
+			if len(rest) > 1 && rest[1] == '\xFF' {
+				value = append(value, nil)
+				rest = rest[2:]
+				continue
+			}
+			return value, rest[1:], nil
+		}
+
+		var e interface{}
+		start := rest
+		e, rest, err = takeTupleElement(rest)
+		if err != nil {
+			return nil, b, AddOffset(err, len(b)-len(start))
+		}
+		value = append(value, e)
+	}
+}
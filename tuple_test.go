@@ -0,0 +1,271 @@
+package sortedbytes_test
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hnakamur/sortedbytes"
+)
+
+func TestTuplePack(t *testing.T) {
+	t.Run("sameAsAppend", func(t *testing.T) {
+		now := time.Date(2020, 5, 17, 12, 34, 56, 789, time.UTC)
+		got := sortedbytes.Tuple{nil, "foo", []byte("bar"), 7, int32(-1), uint64(math.MaxUint64), 1.5, true, now}.Pack()
+		want := []byte{0x00}
+		want = sortedbytes.AppendString(want, "foo")
+		want = sortedbytes.AppendBytes(want, []byte("bar"))
+		want = sortedbytes.AppendVarInt(want, 7)
+		want = sortedbytes.AppendVarInt(want, -1)
+		want = sortedbytes.AppendUint64(want, math.MaxUint64)
+		want = sortedbytes.AppendFloat64(want, 1.5)
+		want = sortedbytes.AppendBool(want, true)
+		want = sortedbytes.AppendTime(want, now)
+		if !bytes.Equal(got, want) {
+			t.Errorf("packed bytes unmatch: got=0x%x, want=0x%x", got, want)
+		}
+	})
+	t.Run("order", func(t *testing.T) {
+		testCases := []sortedbytes.Tuple{
+			{},
+			{nil},
+			{nil, nil},
+			{[]byte("a")},
+			{"a"},
+			{"a", nil},
+			{"a", int64(math.MinInt64)},
+			{"a", -1},
+			{"a", 0},
+			{"a", 1},
+			{"a", 0x100},
+			{"a", uint64(math.MaxUint64)},
+			{"a", mustParseBigInt("0x10000000000000000")},
+			{"a", float32(math.Inf(-1))},
+			{"a", float32(math.NaN())},
+			{"a", math.Inf(-1)},
+			{"a", false},
+			{"a", true},
+			{"a", sortedbytes.UUID{}},
+			{"a", time.Unix(0, 0)},
+			{"b"},
+			{sortedbytes.Tuple{}},
+			{sortedbytes.Tuple{nil}},
+			{sortedbytes.Tuple{nil, 1}},
+			{sortedbytes.Tuple{"a"}},
+			{sortedbytes.Tuple{"a"}, nil},
+			{sortedbytes.Tuple{"a", nil}},
+			{sortedbytes.Tuple{"a", sortedbytes.Tuple{}}},
+			{sortedbytes.Tuple{"a", 1}},
+			{sortedbytes.Tuple{"b"}},
+			{int64(math.MinInt64)},
+		}
+		for i := 1; i < len(testCases); i++ {
+			a := testCases[i-1].Pack()
+			b := testCases[i].Pack()
+			if got, want := bytes.Compare(a, b), -1; got != want {
+				t.Errorf("case %d: compare result unmatch: got=%d, want=%d, a=0x%x, b=0x%x",
+					i, got, want, a, b)
+			}
+		}
+	})
+	t.Run("unsupported", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("got no panic")
+			}
+		}()
+		sortedbytes.Tuple{struct{}{}}.Pack()
+	})
+}
+
+func TestUnpack(t *testing.T) {
+	t.Run("roundtrip", func(t *testing.T) {
+		now := time.Date(2020, 5, 17, 12, 34, 56, 789, time.UTC)
+		testCases := []struct {
+			input sortedbytes.Tuple
+			want  sortedbytes.Tuple
+		}{
+			{input: sortedbytes.Tuple{}, want: nil},
+			{input: sortedbytes.Tuple{nil}, want: sortedbytes.Tuple{nil}},
+			{
+				input: sortedbytes.Tuple{"foo\x00", []byte("\x00bar"), ""},
+				want:  sortedbytes.Tuple{"foo\x00", []byte("\x00bar"), ""},
+			},
+			{
+				input: sortedbytes.Tuple{0, int8(-1), int16(0x100), int32(-0x10000), int64(math.MinInt64), int64(math.MaxInt64)},
+				want:  sortedbytes.Tuple{int64(0), int64(-1), int64(0x100), int64(-0x10000), int64(math.MinInt64), int64(math.MaxInt64)},
+			},
+			{
+				input: sortedbytes.Tuple{uint(1), uint8(2), uint16(3), uint32(4), uint64(math.MaxInt64 + 1)},
+				want:  sortedbytes.Tuple{int64(1), int64(2), int64(3), int64(4), uint64(math.MaxInt64 + 1)},
+			},
+			{
+				input: sortedbytes.Tuple{big.NewInt(5), mustParseBigInt("-0x8000000000000001"), mustParseBigInt("0x10000000000000000")},
+				want:  sortedbytes.Tuple{int64(5), mustParseBigInt("-0x8000000000000001"), mustParseBigInt("0x10000000000000000")},
+			},
+			{
+				input: sortedbytes.Tuple{float32(0.5), -1.5, false, true, now, sortedbytes.UUID{1, 2}, sortedbytes.Versionstamp{UserVersion: 3}},
+				want:  sortedbytes.Tuple{float32(0.5), -1.5, false, true, now, sortedbytes.UUID{1, 2}, sortedbytes.Versionstamp{UserVersion: 3}},
+			},
+			{
+				input: sortedbytes.Tuple{sortedbytes.Tuple{}, sortedbytes.Tuple{nil, "a\x00", sortedbytes.Tuple{nil}, 1}, nil},
+				want:  sortedbytes.Tuple{sortedbytes.Tuple{}, sortedbytes.Tuple{nil, "a\x00", sortedbytes.Tuple{nil}, int64(1)}, nil},
+			},
+		}
+		for i, tc := range testCases {
+			got, err := sortedbytes.Unpack(tc.input.Pack())
+			if err != nil {
+				t.Errorf("case %d: got error: %s", i, err)
+				continue
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("case %d: tuple unmatch: got=%#v, want=%#v", i, got, tc.want)
+			}
+		}
+	})
+	t.Run("fixed", func(t *testing.T) {
+		b := sortedbytes.AppendInt32([]byte(nil), -2)
+		b = sortedbytes.AppendInt64(b, 3)
+		b = sortedbytes.AppendUint32(b, math.MaxUint32)
+		got, err := sortedbytes.Unpack(b)
+		if err != nil {
+			t.Fatalf("got error: %s", err)
+		}
+		if want := (sortedbytes.Tuple{int64(-2), int64(3), int64(math.MaxUint32)}); !reflect.DeepEqual(got, want) {
+			t.Errorf("tuple unmatch: got=%#v, want=%#v", got, want)
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		testCases := [][]byte{
+			[]byte("\x02foo"),
+			[]byte("\x15"),
+			[]byte("\x21\x00"),
+			[]byte("\x14\x03"),
+			[]byte("\x14\xff"),
+		}
+		for i, input := range testCases {
+			_, err := sortedbytes.Unpack(input)
+			if err == nil {
+				t.Errorf("case %d: got no error", i)
+			}
+		}
+	})
+}
+
+func TestNested(t *testing.T) {
+	t.Run("sameAsAppend", func(t *testing.T) {
+		got := sortedbytes.AppendNested([]byte(nil), sortedbytes.Tuple{nil, "a", sortedbytes.Tuple{nil}})
+		want := []byte("\x05\x00\xff\x02a\x00\x05\x00\xff\x00\x00")
+		if !bytes.Equal(got, want) {
+			t.Errorf("encoded bytes unmatch: got=0x%x, want=0x%x", got, want)
+		}
+	})
+	t.Run("rest", func(t *testing.T) {
+		b := sortedbytes.AppendNested([]byte(nil), sortedbytes.Tuple{"a"})
+		b = sortedbytes.AppendInt32(b, 1)
+		got, rest, err := sortedbytes.TakeNested(b)
+		if err != nil {
+			t.Fatalf("got error: %s", err)
+		}
+		if want := (sortedbytes.Tuple{"a"}); !reflect.DeepEqual(got, want) {
+			t.Errorf("tuple unmatch: got=%#v, want=%#v", got, want)
+		}
+		if want := sortedbytes.AppendInt32([]byte(nil), 1); !bytes.Equal(rest, want) {
+			t.Errorf("rest unmatch: got=0x%x, want=0x%x", rest, want)
+		}
+	})
+	t.Run("empty", func(t *testing.T) {
+		testCases := []sortedbytes.Tuple{
+			{sortedbytes.Tuple{}},
+			{"a", sortedbytes.Tuple{sortedbytes.Tuple{}}, nil},
+		}
+		for i, input := range testCases {
+			got, err := sortedbytes.Unpack(input.Pack())
+			if err != nil {
+				t.Errorf("case %d: got error: %s", i, err)
+				continue
+			}
+			if !reflect.DeepEqual(got, input) {
+				t.Errorf("case %d: tuple unmatch: got=%#v, want=%#v", i, got, input)
+			}
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		testCases := [][]byte{
+			nil,
+			[]byte("\x02a\x00"),
+			[]byte("\x05\x02a\x00"),
+			[]byte("\x05\x15\x00"),
+		}
+		for i, input := range testCases {
+			_, rest, err := sortedbytes.TakeNested(input)
+			if err == nil {
+				t.Errorf("case %d: got no error", i)
+			}
+			if !bytes.Equal(rest, input) {
+				t.Errorf("case %d: rest unmatch: got=0x%x, want=0x%x", i, rest, input)
+			}
+		}
+	})
+}
+
+func TestTuplePackWithVersionstamp(t *testing.T) {
+	t.Run("offset", func(t *testing.T) {
+		testCases := []struct {
+			input sortedbytes.Tuple
+			want  int
+		}{
+			{input: sortedbytes.Tuple{sortedbytes.IncompleteVersionstamp(0)}, want: 1},
+			{input: sortedbytes.Tuple{"log", nil, sortedbytes.IncompleteVersionstamp(1), 2}, want: 7},
+			{
+				input: sortedbytes.Tuple{
+					sortedbytes.Versionstamp{UserVersion: 1},
+					sortedbytes.Tuple{nil, sortedbytes.IncompleteVersionstamp(2)},
+				},
+				want: 17,
+			},
+		}
+		for i, tc := range testCases {
+			got, offset, err := tc.input.PackWithVersionstamp()
+			if err != nil {
+				t.Errorf("case %d: got error: %s", i, err)
+				continue
+			}
+			if want := tc.input.Pack(); !bytes.Equal(got, want) {
+				t.Errorf("case %d: packed bytes unmatch: got=0x%x, want=0x%x", i, got, want)
+			}
+			if offset != tc.want {
+				t.Errorf("case %d: offset unmatch: got=%d, want=%d", i, offset, tc.want)
+			}
+			if want := bytes.Repeat([]byte{0xff}, 10); !bytes.Equal(got[offset:offset+10], want) {
+				t.Errorf("case %d: placeholder unmatch: got=0x%x, want=0x%x", i, got[offset:offset+10], want)
+			}
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		testCases := []struct {
+			input sortedbytes.Tuple
+			want  error
+		}{
+			{input: sortedbytes.Tuple{}, want: sortedbytes.ErrNoIncompleteVersionstamp},
+			{input: sortedbytes.Tuple{sortedbytes.Versionstamp{}}, want: sortedbytes.ErrNoIncompleteVersionstamp},
+			{
+				input: sortedbytes.Tuple{sortedbytes.IncompleteVersionstamp(0), sortedbytes.IncompleteVersionstamp(1)},
+				want:  sortedbytes.ErrMultipleIncompleteVersionstamps,
+			},
+			{
+				input: sortedbytes.Tuple{sortedbytes.IncompleteVersionstamp(0), sortedbytes.Tuple{sortedbytes.IncompleteVersionstamp(1)}},
+				want:  sortedbytes.ErrMultipleIncompleteVersionstamps,
+			},
+		}
+		for i, tc := range testCases {
+			if _, _, err := tc.input.PackWithVersionstamp(); !errors.Is(err, tc.want) {
+				t.Errorf("case %d: error unmatch: got=%v, want=%v", i, err, tc.want)
+			}
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package sortedbytes
+
+import (
+	"encoding/hex"
+	"io"
+)
+
+// UUID is a 128 bit universally unique identifier.
+type UUID [16]byte
+
+// String returns the canonical form of u like
+// "01234567-89ab-cdef-0123-456789abcdef".
+func (u UUID) String() string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf[:])
+}
+
+// NullUUID represents an UUID that may be null.
+type NullUUID struct {
+	UUID  UUID
+	Valid bool // Valid is true if UUID is not NULL
+}
+
+// AppendNullUUID appends a NullUUID value to dst.
+//
+// You need to store the result of AppendNullUUID like:
+//     dst = sortedbytes.AppendNullUUID(dst, value)
+func AppendNullUUID(dst []byte, value NullUUID) []byte {
+	if value.Valid {
+		return AppendUUID(dst, value.UUID)
+	}
+	return append(dst, typeCodeNull)
+}
+
+// AppendUUID appends an UUID value to dst.
+//
+// The value is encoded as the FDB UUID type code 0x30 followed by
+// the 16 bytes of the value as is.
+//
+// You need to store the result of AppendUUID like:
+//     dst = sortedbytes.AppendUUID(dst, value)
+func AppendUUID(dst []byte, value UUID) []byte {
+	return append(append(dst, typeCodeUUID), value[:]...)
+}
+
+// TakeNullUUID takes a NullUUID value from b and returns it and the rest of b.
+func TakeNullUUID(b []byte) (value NullUUID, rest []byte, err error) {
+	var c byte
+	c, rest, err = takeTypeCode(b)
+	if err != nil {
+		return value, b, newNullDecodeError(b, err, expectedUUID)
+	}
+	if c == typeCodeNull {
+		return value, b[1:], nil
+	}
+	if c != typeCodeUUID {
+		return value, b, newNullDecodeError(b, ErrUnexpectedTypeCode, expectedUUID)
+	}
+	var v UUID
+	v, rest, err = takeUUIDValue(rest)
+	if err != nil {
+		return value, b, newNullDecodeError(b, err, expectedUUID)
+	}
+	return NullUUID{Valid: true, UUID: v}, rest, nil
+}
+
+// TakeUUID takes an UUID value from b and returns it and the rest of b.
+func TakeUUID(b []byte) (value UUID, rest []byte, err error) {
+	rest, err = expectTypeCode(b, typeCodeUUID)
+	if err != nil {
+		return UUID{}, b, newDecodeError(b, err, expectedUUID)
+	}
+	value, rest, err = takeUUIDValue(rest)
+	if err != nil {
+		return UUID{}, b, newDecodeError(b, err, expectedUUID)
+	}
+	return value, rest, nil
+}
+
+func takeUUIDValue(b []byte) (value UUID, rest []byte, err error) {
+	if len(b) < len(value) {
+		return UUID{}, nil, io.ErrUnexpectedEOF
+	}
+	copy(value[:], b)
+	return value, b[len(value):], nil
+}
@@ -0,0 +1,119 @@
+package sortedbytes_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hnakamur/sortedbytes"
+)
+
+func TestUUIDString(t *testing.T) {
+	u := sortedbytes.UUID{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef}
+	if got, want := u.String(), "01234567-89ab-cdef-0123-456789abcdef"; got != want {
+		t.Errorf("string unmatch: got=%s, want=%s", got, want)
+	}
+}
+
+func TestAppendNullUUID(t *testing.T) {
+	t.Run("order", func(t *testing.T) {
+		testCases := []sortedbytes.NullUUID{
+			{},
+			{Valid: true, UUID: sortedbytes.UUID{}},
+			{Valid: true, UUID: sortedbytes.UUID{15: 1}},
+			{Valid: true, UUID: sortedbytes.UUID{0: 1}},
+			{Valid: true, UUID: sortedbytes.UUID{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		}
+		for i := 1; i < len(testCases); i++ {
+			a := sortedbytes.AppendNullUUID([]byte(nil), testCases[i-1])
+			b := sortedbytes.AppendNullUUID([]byte(nil), testCases[i])
+			if got, want := bytes.Compare(a, b), -1; got != want {
+				t.Errorf("case %d: compare result unmatch: got=%d, want=%d, a=0x%x, b=0x%x",
+					i, got, want, a, b)
+			}
+		}
+	})
+}
+
+func TestTakeNullUUID(t *testing.T) {
+	t.Run("roundtrip", func(t *testing.T) {
+		testCases := []sortedbytes.NullUUID{
+			{},
+			{Valid: true, UUID: sortedbytes.UUID{}},
+			{Valid: true, UUID: sortedbytes.UUID{1, 2, 3, 15: 4}},
+		}
+		for i, input := range testCases {
+			b := sortedbytes.AppendNullUUID([]byte(nil), input)
+			v, rest, err := sortedbytes.TakeNullUUID(b)
+			if err != nil {
+				t.Errorf("case %d: got error: %s", i, err)
+			}
+			if got, want := v, input; got != want {
+				t.Errorf("case %d: value unmatch: got=%+v, want=%+v", i, got, want)
+			}
+			if got, want := len(rest), 0; got != want {
+				t.Errorf("case %d: rest length unmatch: got=%d, want=%d", i, got, want)
+			}
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		testCases := [][]byte{
+			[]byte("\x30"),
+			[]byte("\x30\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00"),
+			[]byte("\x02"),
+		}
+		for i, input := range testCases {
+			_, _, err := sortedbytes.TakeNullUUID(input)
+			if err == nil {
+				t.Errorf("case %d: got no error", i)
+			}
+		}
+	})
+}
+
+func TestAppendUUID(t *testing.T) {
+	u := sortedbytes.UUID{1, 2, 3, 15: 4}
+	got := sortedbytes.AppendUUID([]byte(nil), u)
+	want := append([]byte{0x30}, u[:]...)
+	if !bytes.Equal(got, want) {
+		t.Errorf("encoded bytes unmatch: got=0x%x, want=0x%x", got, want)
+	}
+}
+
+func TestTakeUUID(t *testing.T) {
+	t.Run("roundtrip", func(t *testing.T) {
+		testCases := []sortedbytes.UUID{
+			{},
+			{1, 2, 3, 15: 4},
+		}
+		for i, input := range testCases {
+			b := sortedbytes.AppendUUID([]byte(nil), input)
+			b = append(b, 0x14)
+			v, rest, err := sortedbytes.TakeUUID(b)
+			if err != nil {
+				t.Errorf("case %d: got error: %s", i, err)
+			}
+			if got, want := v, input; got != want {
+				t.Errorf("case %d: value unmatch: got=%s, want=%s", i, got, want)
+			}
+			if got, want := rest, []byte{0x14}; !bytes.Equal(got, want) {
+				t.Errorf("case %d: rest unmatch: got=0x%x, want=0x%x", i, got, want)
+			}
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		testCases := [][]byte{
+			nil,
+			[]byte("\x00"),
+			[]byte("\x30\x00"),
+		}
+		for i, input := range testCases {
+			v, rest, err := sortedbytes.TakeUUID(input)
+			if err == nil {
+				t.Errorf("case %d: got no error", i)
+			}
+			if v != (sortedbytes.UUID{}) || !bytes.Equal(rest, input) {
+				t.Errorf("case %d: unexpected result on error: v=%s, rest=0x%x", i, v, rest)
+			}
+		}
+	})
+}
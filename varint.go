@@ -0,0 +1,138 @@
+package sortedbytes
+
+import (
+	"database/sql"
+	"io"
+	"math"
+	"math/bits"
+)
+
+// AppendNullVarInt appends a NullInt64 value to dst in the compact form.
+//
+// You need to store the result of AppendNullVarInt like:
+//     dst = sortedbytes.AppendNullVarInt(dst, value)
+func AppendNullVarInt(dst []byte, value sql.NullInt64) []byte {
+	if value.Valid {
+		return AppendVarInt(dst, value.Int64)
+	}
+	return append(dst, typeCodeNull)
+}
+
+// AppendVarInt appends an int64 value to dst in the compact form.
+//
+// Unlike AppendInt64 which always writes 8 bytes after the type code,
+// AppendVarInt writes the minimal number of bytes and the type code tells
+// the length like the FDB tuple layer does. The only exception is that
+// a value which needs 5 bytes is written in 6 bytes, since the type codes
+// for 5 bytes are used for int32 values in this package.
+//
+// Values encoded by AppendVarInt keep the order among themselves, but not
+// with values encoded by AppendInt32 or AppendInt64, so do not mix them
+// for the same key component.
+//
+// You need to store the result of AppendVarInt like:
+//     dst = sortedbytes.AppendVarInt(dst, value)
+func AppendVarInt(dst []byte, value int64) []byte {
+	if value == 0 {
+		return append(dst, typeCodeIntZero)
+	}
+
+	if value > 0 {
+		u := uint64(value)
+		n := varIntPayloadLen(u)
+		dst = append(dst, typeCodeIntZero+byte(n))
+		return appendUintBytes(dst, u, n)
+	}
+
+	u := uint64(-value)
+	n := varIntPayloadLen(u)
+	dst = append(dst, typeCodeIntZero-byte(n))
+	return appendUintBytes(dst, ^u, n)
+}
+
+// varIntPayloadLen returns the length of the payload for the absolute value
+// u of a non-zero integer.
+func varIntPayloadLen(u uint64) int {
+	n := (bits.Len64(u) + 7) / 8
+	if n == 5 {
+		n = 6
+	}
+	return n
+}
+
+// appendUintBytes appends the lower n bytes of u in big endian to dst.
+func appendUintBytes(dst []byte, u uint64, n int) []byte {
+	for i := n - 1; i >= 0; i-- {
+		dst = append(dst, byte(u>>(8*uint(i))))
+	}
+	return dst
+}
+
+// TakeNullVarInt takes a sql.NullInt64 value from b and returns it and the rest of b.
+func TakeNullVarInt(b []byte) (value sql.NullInt64, rest []byte, err error) {
+	var c byte
+	c, rest, err = takeTypeCode(b)
+	if err != nil {
+		return value, b, newNullDecodeError(b, err, expectedVarInt)
+	}
+	if c == typeCodeNull {
+		return value, b[1:], nil
+	}
+	var v int64
+	v, rest, err = takeVarIntValue(c, rest)
+	if err != nil {
+		return value, b, newNullDecodeError(b, err, expectedVarInt)
+	}
+	return sql.NullInt64{Valid: true, Int64: v}, rest, nil
+}
+
+// TakeVarInt takes an int64 value from b and returns it and the rest of b.
+//
+// TakeVarInt accepts values encoded by AppendVarInt as well as
+// values encoded by AppendInt32 and AppendInt64.
+func TakeVarInt(b []byte) (value int64, rest []byte, err error) {
+	var c byte
+	c, rest, err = takeTypeCode(b)
+	if err != nil {
+		return 0, b, newDecodeError(b, err, expectedVarInt)
+	}
+	value, rest, err = takeVarIntValue(c, rest)
+	if err != nil {
+		return 0, b, newDecodeError(b, err, expectedVarInt)
+	}
+	return value, rest, nil
+}
+
+func takeVarIntValue(c byte, b []byte) (value int64, rest []byte, err error) {
+	if c == typeCodeIntZero {
+		return 0, b, nil
+	}
+	if c < typeCodeNegativeInt64 || c > typeCodePositiveInt64 {
+		return 0, nil, ErrUnexpectedTypeCode
+	}
+
+	n := intPayloadLen(c)
+	if len(b) < n {
+		return 0, nil, io.ErrUnexpectedEOF
+	}
+	var u uint64
+	for _, d := range b[:n] {
+		u = u<<8 | uint64(d)
+	}
+
+	if c > typeCodeIntZero {
+		if u > math.MaxInt64 {
+			return 0, nil, ErrValueOutOfRange
+		}
+		return int64(u), b[n:], nil
+	}
+
+	u = ^u
+	if n < 8 {
+		u &= 1<<(8*uint(n)) - 1
+	}
+	if u > -math.MinInt64 {
+		return 0, nil, ErrValueOutOfRange
+	}
+	return -int64(u), b[n:], nil
+}
@@ -0,0 +1,221 @@
+package sortedbytes_test
+
+import (
+	"bytes"
+	"database/sql"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/hnakamur/sortedbytes"
+)
+
+func TestAppendNullVarInt(t *testing.T) {
+	t.Run("order", func(t *testing.T) {
+		testCases := []struct {
+			a, b sql.NullInt64
+		}{
+			{
+				a: sql.NullInt64{Valid: false, Int64: 0},
+				b: sql.NullInt64{Valid: true, Int64: math.MinInt64},
+			},
+			{
+				a: sql.NullInt64{Valid: true, Int64: -1},
+				b: sql.NullInt64{Valid: true, Int64: 0},
+			},
+			{
+				a: sql.NullInt64{Valid: true, Int64: 0xff},
+				b: sql.NullInt64{Valid: true, Int64: 0x100},
+			},
+		}
+		for i, tc := range testCases {
+			a := sortedbytes.AppendNullVarInt([]byte(nil), tc.a)
+			b := sortedbytes.AppendNullVarInt([]byte(nil), tc.b)
+			if got, want := bytes.Compare(a, b), -1; got != want {
+				t.Errorf("case %d: compare result unmatch: got=%d, want=%d, a=0x%x, b=0x%x",
+					i, got, want, a, b)
+			}
+		}
+	})
+}
+
+func TestTakeNullVarInt(t *testing.T) {
+	t.Run("roundtrip", func(t *testing.T) {
+		testCases := []sql.NullInt64{
+			{Valid: false, Int64: 0},
+			{Valid: true, Int64: 0},
+			{Valid: true, Int64: math.MinInt64},
+			{Valid: true, Int64: -1},
+			{Valid: true, Int64: 1},
+			{Valid: true, Int64: math.MaxInt64},
+		}
+		for i, input := range testCases {
+			b := sortedbytes.AppendNullVarInt([]byte(nil), input)
+			v, rest, err := sortedbytes.TakeNullVarInt(b)
+			if err != nil {
+				t.Errorf("case %d: got error: %s", i, err)
+			}
+			if got, want := v, input; !reflect.DeepEqual(got, want) {
+				t.Errorf("case %d: value unmatch: got=%+v, want=%+v", i, got, want)
+			}
+			if got, want := len(rest), 0; got != want {
+				t.Errorf("case %d: rest length unmatch: got=%d, want=%d", i, got, want)
+			}
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		testCases := [][]byte{
+			[]byte("\x15"),
+			[]byte("\x02"),
+		}
+		for i, input := range testCases {
+			_, _, err := sortedbytes.TakeNullVarInt(input)
+			if err == nil {
+				t.Errorf("case %d: got no error", i)
+			}
+		}
+	})
+}
+
+func TestAppendVarInt(t *testing.T) {
+	t.Run("order", func(t *testing.T) {
+		testCases := []int64{
+			math.MinInt64,
+			math.MinInt64 + 1,
+			-0x100_0000_0000_0000,
+			-0xff_ffff_ffff_ffff,
+			-0x1_0000_0000_0000,
+			-0xffff_ffff_ffff,
+			-0x100_0000_0000,
+			-0xff_ffff_ffff,
+			-0x1_0000_0000,
+			-0xffff_ffff,
+			-0x100_0000,
+			-0xff_ffff,
+			-0x1_0000,
+			-0xffff,
+			-0x100,
+			-0xff,
+			-2,
+			-1,
+			0,
+			1,
+			2,
+			0xff,
+			0x100,
+			0xffff,
+			0x1_0000,
+			0xff_ffff,
+			0x100_0000,
+			0xffff_ffff,
+			0x1_0000_0000,
+			0xff_ffff_ffff,
+			0x100_0000_0000,
+			0xffff_ffff_ffff,
+			0x1_0000_0000_0000,
+			0xff_ffff_ffff_ffff,
+			0x100_0000_0000_0000,
+			math.MaxInt64 - 1,
+			math.MaxInt64,
+		}
+		for i := 1; i < len(testCases); i++ {
+			a := sortedbytes.AppendVarInt([]byte(nil), testCases[i-1])
+			b := sortedbytes.AppendVarInt([]byte(nil), testCases[i])
+			if got, want := bytes.Compare(a, b), -1; got != want {
+				t.Errorf("case %d: compare result unmatch: got=%d, want=%d, a=0x%x, b=0x%x",
+					i, got, want, a, b)
+			}
+		}
+	})
+	t.Run("length", func(t *testing.T) {
+		testCases := []struct {
+			input int64
+			want  []byte
+		}{
+			{input: 0, want: []byte("\x14")},
+			{input: 7, want: []byte("\x15\x07")},
+			{input: -7, want: []byte("\x13\xf8")},
+			{input: 0x1234, want: []byte("\x16\x12\x34")},
+			{input: 0xffff_ffff, want: []byte("\x18\xff\xff\xff\xff")},
+			{input: 0x1_0000_0000, want: []byte("\x1a\x00\x01\x00\x00\x00\x00")},
+			{input: -0x1_0000_0000, want: []byte("\x0e\xff\xfe\xff\xff\xff\xff")},
+			{input: math.MaxInt64, want: []byte("\x1c\x7f\xff\xff\xff\xff\xff\xff\xff")},
+		}
+		for i, tc := range testCases {
+			got := sortedbytes.AppendVarInt([]byte(nil), tc.input)
+			if !bytes.Equal(got, tc.want) {
+				t.Errorf("case %d: encoded bytes unmatch: got=0x%x, want=0x%x", i, got, tc.want)
+			}
+		}
+	})
+}
+
+func TestTakeVarInt(t *testing.T) {
+	t.Run("roundtrip", func(t *testing.T) {
+		testCases := []int64{
+			math.MinInt64,
+			-0x1_0000_0000,
+			-0xffff_ffff,
+			-0x100,
+			-1,
+			0,
+			1,
+			0xff,
+			0xffff_ffff,
+			0x1_0000_0000,
+			0xff_ffff_ffff,
+			math.MaxInt64,
+		}
+		for i, input := range testCases {
+			b := sortedbytes.AppendVarInt([]byte(nil), input)
+			v, rest, err := sortedbytes.TakeVarInt(b)
+			if err != nil {
+				t.Errorf("case %d: got error: %s", i, err)
+			}
+			if got, want := v, input; got != want {
+				t.Errorf("case %d: value unmatch: got=%d, want=%d", i, got, want)
+			}
+			if got, want := len(rest), 0; got != want {
+				t.Errorf("case %d: rest length unmatch: got=%d, want=%d", i, got, want)
+			}
+		}
+	})
+	t.Run("fixed", func(t *testing.T) {
+		testCases := []int64{math.MinInt64, math.MinInt32, -1, 0, 1, math.MaxInt32, math.MaxInt64}
+		for i, input := range testCases {
+			b := sortedbytes.AppendInt64([]byte(nil), input)
+			if v, _, err := sortedbytes.TakeVarInt(b); err != nil {
+				t.Errorf("case %d: int64: got error: %s", i, err)
+			} else if v != input {
+				t.Errorf("case %d: int64: value unmatch: got=%d, want=%d", i, v, input)
+			}
+
+			if input < math.MinInt32 || input > math.MaxInt32 {
+				continue
+			}
+			b = sortedbytes.AppendInt32([]byte(nil), int32(input))
+			if v, _, err := sortedbytes.TakeVarInt(b); err != nil {
+				t.Errorf("case %d: int32: got error: %s", i, err)
+			} else if v != input {
+				t.Errorf("case %d: int32: value unmatch: got=%d, want=%d", i, v, input)
+			}
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		testCases := [][]byte{
+			[]byte("\x15"),
+			[]byte("\x13"),
+			[]byte("\x1a\x00\x01\x00\x00\x00"),
+			[]byte("\x1c\x80\x00\x00\x00\x00\x00\x00\x00"),
+			[]byte("\x0c\x7f\xff\xff\xff\xff\xff\xff\xfe"),
+			[]byte("\x1d\x09\x01\x00\x00\x00\x00\x00\x00\x00\x00"),
+			[]byte("\x02"),
+		}
+		for i, input := range testCases {
+			_, _, err := sortedbytes.TakeVarInt(input)
+			if err == nil {
+				t.Errorf("case %d: got no error", i)
+			}
+		}
+	})
+}
@@ -0,0 +1,67 @@
+package sortedbytes
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// Versionstamp is a 12 byte value which consists of the 10 byte
+// transaction version assigned by the storage engine at commit time and
+// the 2 byte user version which orders values within a transaction.
+type Versionstamp struct {
+	TransactionVersion [10]byte
+	UserVersion        uint16
+}
+
+// IncompleteVersionstamp returns a Versionstamp with userVersion whose
+// transaction version is not assigned yet. All bytes of the transaction
+// version are 0xFF, which is the placeholder to be filled in by
+// the storage engine.
+func IncompleteVersionstamp(userVersion uint16) Versionstamp {
+	v := Versionstamp{UserVersion: userVersion}
+	for i := range v.TransactionVersion {
+		v.TransactionVersion[i] = 0xFF
+	}
+	return v
+}
+
+// IsComplete reports whether the transaction version of v is assigned.
+func (v Versionstamp) IsComplete() bool {
+	for _, c := range v.TransactionVersion {
+		if c != 0xFF {
+			return true
+		}
+	}
+	return false
+}
+
+// AppendVersionstamp appends a Versionstamp value to dst.
+//
+// The value is encoded as the FDB versionstamp type code 0x33 followed by
+// the transaction version and the user version in big endian, so
+// the placeholder of an incomplete versionstamp starts at len(dst)+1
+// in the result.
+//
+// You need to store the result of AppendVersionstamp like:
+//     dst = sortedbytes.AppendVersionstamp(dst, value)
+func AppendVersionstamp(dst []byte, value Versionstamp) []byte {
+	dst = append(append(dst, typeCodeVersionstamp), value.TransactionVersion[:]...)
+	var b [2]byte
+	binary.BigEndian.PutUint16(b[:], value.UserVersion)
+	return append(dst, b[:]...)
+}
+
+// TakeVersionstamp takes a Versionstamp value from b and returns it and
+// the rest of b.
+func TakeVersionstamp(b []byte) (value Versionstamp, rest []byte, err error) {
+	rest, err = expectTypeCode(b, typeCodeVersionstamp)
+	if err != nil {
+		return Versionstamp{}, b, newDecodeError(b, err, expectedVersionstamp)
+	}
+	if len(rest) < 12 {
+		return Versionstamp{}, b, newDecodeError(b, io.ErrUnexpectedEOF, expectedVersionstamp)
+	}
+	copy(value.TransactionVersion[:], rest)
+	value.UserVersion = binary.BigEndian.Uint16(rest[10:12])
+	return value, rest[12:], nil
+}
@@ -0,0 +1,94 @@
+package sortedbytes_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hnakamur/sortedbytes"
+)
+
+func TestVersionstamp(t *testing.T) {
+	t.Run("incomplete", func(t *testing.T) {
+		v := sortedbytes.IncompleteVersionstamp(3)
+		if v.IsComplete() {
+			t.Error("IsComplete() = true for incomplete versionstamp")
+		}
+		if got, want := v.UserVersion, uint16(3); got != want {
+			t.Errorf("user version unmatch: got=%d, want=%d", got, want)
+		}
+		v.TransactionVersion[9] = 0
+		if !v.IsComplete() {
+			t.Error("IsComplete() = false for complete versionstamp")
+		}
+	})
+}
+
+func TestAppendVersionstamp(t *testing.T) {
+	t.Run("sameAsBytes", func(t *testing.T) {
+		v := sortedbytes.Versionstamp{
+			TransactionVersion: [10]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+			UserVersion:        0x0a0b,
+		}
+		got := sortedbytes.AppendVersionstamp([]byte{0x14}, v)
+		want := []byte("\x14\x33\x00\x01\x02\x03\x04\x05\x06\x07\x08\x09\x0a\x0b")
+		if !bytes.Equal(got, want) {
+			t.Errorf("encoded bytes unmatch: got=0x%x, want=0x%x", got, want)
+		}
+	})
+	t.Run("order", func(t *testing.T) {
+		testCases := []sortedbytes.Versionstamp{
+			{},
+			{UserVersion: 1},
+			{TransactionVersion: [10]byte{9: 1}},
+			{TransactionVersion: [10]byte{0: 1}},
+			sortedbytes.IncompleteVersionstamp(0),
+		}
+		for i := 1; i < len(testCases); i++ {
+			a := sortedbytes.AppendVersionstamp([]byte(nil), testCases[i-1])
+			b := sortedbytes.AppendVersionstamp([]byte(nil), testCases[i])
+			if got, want := bytes.Compare(a, b), -1; got != want {
+				t.Errorf("case %d: compare result unmatch: got=%d, want=%d, a=0x%x, b=0x%x",
+					i, got, want, a, b)
+			}
+		}
+	})
+}
+
+func TestTakeVersionstamp(t *testing.T) {
+	t.Run("roundtrip", func(t *testing.T) {
+		testCases := []sortedbytes.Versionstamp{
+			{},
+			{TransactionVersion: [10]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, UserVersion: 0xffff},
+			sortedbytes.IncompleteVersionstamp(1),
+		}
+		for i, input := range testCases {
+			b := sortedbytes.AppendVersionstamp([]byte(nil), input)
+			v, rest, err := sortedbytes.TakeVersionstamp(b)
+			if err != nil {
+				t.Errorf("case %d: got error: %s", i, err)
+			}
+			if got, want := v, input; got != want {
+				t.Errorf("case %d: value unmatch: got=%+v, want=%+v", i, got, want)
+			}
+			if got, want := len(rest), 0; got != want {
+				t.Errorf("case %d: rest length unmatch: got=%d, want=%d", i, got, want)
+			}
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		testCases := [][]byte{
+			nil,
+			[]byte("\x30"),
+			[]byte("\x33\x00\x01\x02\x03\x04\x05\x06\x07\x08\x09\x0a"),
+		}
+		for i, input := range testCases {
+			v, rest, err := sortedbytes.TakeVersionstamp(input)
+			if err == nil {
+				t.Errorf("case %d: got no error", i)
+			}
+			if v != (sortedbytes.Versionstamp{}) || !bytes.Equal(rest, input) {
+				t.Errorf("case %d: unexpected result on error: v=%+v, rest=0x%x", i, v, rest)
+			}
+		}
+	})
+}